@@ -6,8 +6,8 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"math/rand"
 	"os"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -19,6 +19,20 @@ type battlePokemon struct {
 	pokemon Pokemon
 	current int
 	max     int
+	stages  statStages
+	status  string
+	entered bool
+	side    string
+	label   string
+	// trapTurns is how many more rounds the Pokemon is trapped, or
+	// trapUntilSwitch.
+	trapTurns int
+	// sleepTurns is how many more of its turns a sleeping Pokemon skips.
+	sleepTurns int
+}
+
+func (p *battlePokemon) displayName() string {
+	return p.label + p.pokemon.name
 }
 
 type pokemonSelection struct {
@@ -29,12 +43,124 @@ type pokemonSelection struct {
 
 var errSelectionCancelled = errors.New("selection cancelled")
 
+var errNoEffect = errors.New("item has no effect")
+
 const (
 	statusNone      = "none"
 	statusSleep     = "sleep"
 	statusParalysis = "paralysis"
 )
 
+type battleSide struct {
+	name   string
+	team   []battlePokemon
+	active int
+}
+
+func newBattleSide(name, label string, members []Pokemon) battleSide {
+	side := battleSide{name: name, active: -1}
+	for _, member := range members {
+		battle := newBattlePokemon(member)
+		battle.side = name
+		battle.label = label
+		side.team = append(side.team, battle)
+	}
+	return side
+}
+
+func (s *battleSide) current() *battlePokemon {
+	if s.active < 0 || s.active >= len(s.team) {
+		return nil
+	}
+	return &s.team[s.active]
+}
+
+// bringIn makes the team member at index active, clearing the stat stages
+// and traps of the Pokemon it replaces.
+func (s *battleSide) bringIn(index int) {
+	if previous := s.current(); previous != nil {
+		previous.stages = make(statStages)
+		previous.trapTurns = 0
+	}
+	s.active = index
+}
+
+func (s *battleSide) remaining() int {
+	count := 0
+	for _, member := range s.team {
+		if member.current > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// battleSession is a battle between the player's party and a foe side, which
+// is either a single wild Pokemon or an NPC trainer's team (trainer != nil).
+type battleSession struct {
+	c       *config
+	reader  *bufio.Reader
+	r       *rand.Rand
+	rec     *battleRecorder
+	player  battleSide
+	foe     battleSide
+	trainer *npcTrainer
+	ai      BattleAI
+	// escapes counts the player's attempts to run from a wild Pokemon.
+	escapes int
+}
+
+// turnOutcome is what one of the player's actions did to the battle.
+type turnOutcome int
+
+const (
+	// turnFree means nothing happened, e.g. a cancelled prompt or a blocked
+	// escape, and the player chooses again in the same round.
+	turnFree turnOutcome = iota
+	turnUsed
+	turnOver
+)
+
+// usedTurn turns the result of resolving a round into its outcome.
+func usedTurn(done bool, err error) (turnOutcome, error) {
+	if done {
+		return turnOver, err
+	}
+	return turnUsed, err
+}
+
+// battleTurn is what an AI-controlled side does this turn: use move, or
+// switch to the team member at switchTo when it is not -1.
+type battleTurn struct {
+	move     PokemonMove
+	switchTo int
+}
+
+func newBattleSession(c *config, rec *battleRecorder, seed int64, team []Pokemon) *battleSession {
+	return &battleSession{
+		c:      c,
+		reader: bufio.NewReader(os.Stdin),
+		r:      rand.New(rand.NewSource(seed)),
+		rec:    rec,
+		player: newBattleSide(sidePlayer, "", team),
+		ai:     randomAI{},
+	}
+}
+
+func battleReadyParty(c *config) ([]Pokemon, error) {
+	if len(c.Pokedex) == 0 {
+		return nil, errors.New("No Pokemon in your Pokedex")
+	}
+	team := partyPokemon(c)
+	if len(team) == 0 {
+		return nil, errors.New("Your party is empty, add Pokemon with party add")
+	}
+	if !partyCanBattle(team) {
+		return nil, errors.New("All your party Pokemon have fainted, visit the Pokemon Center with heal")
+	}
+	return team, nil
+}
+
 func commandBattle(c *config, name ...string) error {
 	if len(name) == 0 {
 		return errors.New("Enter a Pokemon to battle")
@@ -42,16 +168,26 @@ func commandBattle(c *config, name ...string) error {
 	if len(name) > 1 {
 		return errors.New("Command battle takes a single Pokemon")
 	}
-	if len(c.Pokedex) == 0 {
-		return errors.New("No Pokemon in your Pokedex")
+	encounters, err := areaEncounters(c)
+	if err != nil {
+		return err
 	}
+	levels, found := encounters[name[0]]
+	if !found {
+		return fmt.Errorf("There's no wild %s in %s", name[0], c.Area)
+	}
+	return battleWild(c, name[0], levels)
+}
 
-	reader := bufio.NewReader(os.Stdin)
-
-	fmt.Println()
-	fmt.Printf("A wild %s appeared!\n", name[0])
+// battleWild fights a wild Pokemon met at a level within levels. A zero
+// range keeps the level the Pokemon is built at.
+func battleWild(c *config, name string, levels levelRange) error {
+	team, err := battleReadyParty(c)
+	if err != nil {
+		return err
+	}
 
-	wildResp, err := c.pokeapiClient.GetPokemon(name[0])
+	wildResp, err := c.pokeapiClient.GetPokemon(name)
 	if err != nil {
 		return err
 	}
@@ -60,200 +196,588 @@ func commandBattle(c *config, name ...string) error {
 		return err
 	}
 	wild.dateCaught = time.Time{}
-	wildBattle := battlePokemon{pokemon: wild, max: maxHP(wild)}
-	wildBattle.current = wildBattle.max
-	wildStatus := statusNone
-	var selection pokemonSelection
-	var playerBattle battlePokemon
-	playerSelected := false
 
+	// Only battles that actually start are recorded.
+	seed := time.Now().UnixNano()
+	rec := newBattleRecorder(c, seed)
+	defer func() {
+		if err := rec.finish(c); err != nil {
+			fmt.Printf("Warning: failed to record battle: %v\n", err)
+		}
+	}()
+
+	fmt.Println()
+	rec.say(eventIntro, "A wild %s appeared!", name)
+	b := newBattleSession(c, rec, seed, team)
+	if levels.min > 0 {
+		if err := setWildLevel(c, &wild, levels.roll(b.r)); err != nil {
+			return err
+		}
+		rec.say(eventIntro, "It's level %d.", wild.level)
+	}
+	c.EncounterChain.extend(wild.species)
+	if rollShiny(b.r, shinyOdds(c), c.EncounterChain) {
+		wild.shiny = true
+		rec.say(eventIntro, "Whoa! The wild %s is sparkling! It's shiny!", wild.name)
+	}
+	rec.addParticipant(sideWild, wild)
+
+	b.foe = newBattleSide(sideWild, "Wild ", []Pokemon{wild})
+	b.foe.active = 0
+	b.foe.team[0].entered = true
+	return b.run()
+}
+
+func (b *battleSession) run() error {
 	round := 1
 	for {
-		fmt.Printf("\nRound %d\n", round)
-		if playerSelected {
-			fmt.Printf("Your %s HP: %d/%d\n", playerBattle.pokemon.name, playerBattle.current, playerBattle.max)
-		} else {
-			fmt.Println("Your Pokemon: (not selected)")
-		}
-		fmt.Printf("Wild %s HP: %d/%d\n", wildBattle.pokemon.name, wildBattle.current, wildBattle.max)
+		b.rec.round = round
+		b.printStatus(round)
 
-		action, cancelled, err := promptChoice(reader, "Choose action: 1) Fight 2) Catch 3) Run 4) Item > ", 4)
+		action, cancelled, err := promptChoice(b.reader, "Choose action: 1) Fight 2) Catch 3) Run 4) Item 5) Switch > ", 5)
 		if err != nil {
 			return err
 		}
 		if cancelled {
+			if b.trainer != nil {
+				b.rec.result = resultLoss
+				b.rec.say(eventRun, "You forfeited the battle against %s.", b.trainer.DisplayName)
+				b.syncParty()
+				return nil
+			}
+			b.rec.result = resultCancelled
 			fmt.Println("Battle cancelled")
 			return nil
 		}
 
+		outcome := turnFree
 		switch action {
 		case 1:
-			if !playerSelected {
-				selection, err = choosePlayerPokemon(c, reader)
-				if err != nil {
+			if b.player.current() == nil {
+				if err := b.sendOut(false); err != nil {
 					if errors.Is(err, errSelectionCancelled) {
+						b.rec.result = resultCancelled
 						return nil
 					}
 					return err
 				}
-				player := c.Pokedex[selection.key][selection.index]
-				if err := applyRestXP(c, &player); err != nil {
-					return err
-				}
-				playerBattle = battlePokemon{pokemon: player, max: maxHP(player)}
-				playerBattle.current = playerBattle.max
-				playerSelected = true
-			}
-			move := chooseMove(reader, playerBattle.pokemon)
-			wildMove := chooseWildMove(wildBattle.pokemon)
-			playerFirst := decideFirst(move, wildMove, playerBattle.pokemon, wildBattle.pokemon)
-			if playerFirst {
-				resolveAttack(&playerBattle, &wildBattle, move, true)
-				if wildBattle.current <= 0 {
-					fmt.Printf("Wild %s fainted!\n", wildBattle.pokemon.name)
-					err = awardBattleXP(c, &playerBattle.pokemon, wildBattle.pokemon.baseExperience)
-					if err == nil {
-						syncPlayerPokemon(c, selection, playerBattle.pokemon)
-						saveUserData(c)
-					}
-					grantRandomSupplies(c, "Battle win")
-					return err
-				}
-				resolveAttack(&wildBattle, &playerBattle, wildMove, false)
-			} else {
-				resolveAttack(&wildBattle, &playerBattle, wildMove, false)
-				if playerBattle.current <= 0 {
-					fmt.Printf("%s fainted!\n", playerBattle.pokemon.name)
-					syncPlayerPokemon(c, selection, playerBattle.pokemon)
-					saveUserData(c)
-					return nil
-				}
-				resolveAttack(&playerBattle, &wildBattle, move, true)
-			}
-			if playerBattle.current <= 0 {
-				fmt.Printf("%s fainted!\n", playerBattle.pokemon.name)
-				syncPlayerPokemon(c, selection, playerBattle.pokemon)
-				saveUserData(c)
-				return nil
-			}
-			if wildBattle.current <= 0 {
-				fmt.Printf("Wild %s fainted!\n", wildBattle.pokemon.name)
-				err = awardBattleXP(c, &playerBattle.pokemon, wildBattle.pokemon.baseExperience)
-				if err == nil {
-					syncPlayerPokemon(c, selection, playerBattle.pokemon)
-					saveUserData(c)
-				}
-				grantRandomSupplies(c, "Battle win")
-				return err
 			}
+			outcome, err = b.fightTurn()
 		case 2:
-			caught, err := attemptCatchInBattle(reader, c, &wildBattle, wildStatus)
-			if err != nil {
-				return err
-			}
-			if caught {
-				fmt.Printf("%s was caught!\n", wildBattle.pokemon.name)
-				appendCaughtPokemon(c, wildBattle.pokemon)
-				if playerSelected {
-					err = awardCaptureXP(c, &playerBattle.pokemon, wildBattle.pokemon.baseExperience)
-					if err == nil {
-						syncPlayerPokemon(c, selection, playerBattle.pokemon)
-						saveUserData(c)
-					}
-				} else {
-					saveUserData(c)
-				}
-				grantRandomSupplies(c, "Catch")
-				return err
-			}
-			fmt.Printf("%s escaped the ball!\n", wildBattle.pokemon.name)
-			if playerSelected {
-				wildMove := chooseWildMove(wildBattle.pokemon)
-				resolveAttack(&wildBattle, &playerBattle, wildMove, false)
-				if playerBattle.current <= 0 {
-					fmt.Printf("%s fainted!\n", playerBattle.pokemon.name)
-					syncPlayerPokemon(c, selection, playerBattle.pokemon)
-					saveUserData(c)
-					return nil
-				}
-			}
+			outcome, err = b.catchTurn()
 		case 3:
-			fmt.Println("You ran away.")
-			if playerSelected {
-				syncPlayerPokemon(c, selection, playerBattle.pokemon)
-				saveUserData(c)
-			}
-			return nil
+			outcome, err = b.runTurn()
 		case 4:
-			status, err := applyPotion(reader, c)
-			if err != nil {
-				return err
+			outcome, err = b.itemTurn()
+		case 5:
+			outcome, err = b.switchTurn()
+		}
+		if outcome == turnOver || err != nil {
+			return err
+		}
+		if outcome == turnFree {
+			continue
+		}
+		if done, err := b.endRound(); done || err != nil {
+			return err
+		}
+
+		if active := b.player.current(); active != nil {
+			active.tickTrap()
+		}
+		round++
+	}
+}
+
+func (b *battleSession) printStatus(round int) {
+	fmt.Printf("\nRound %d\n", round)
+	if active := b.player.current(); active != nil {
+		fmt.Printf("Your %s HP: %d/%d%s\n", active.pokemon.name, active.current, active.max, formatStages(active.stages))
+	} else {
+		fmt.Println("Your Pokemon: (not selected)")
+	}
+	fmt.Printf("Party: %d/%d able to battle\n", b.player.remaining(), len(b.player.team))
+	if foe := b.foe.current(); foe != nil {
+		fmt.Printf("%s HP: %d/%d%s\n", foe.displayName(), foe.current, foe.max, formatStages(foe.stages))
+	}
+	if b.trainer != nil {
+		fmt.Printf("%s: %d/%d able to battle\n", b.trainer.DisplayName, b.foe.remaining(), len(b.foe.team))
+	}
+}
+
+// sendOut asks the player for the next party member to bring in. Forced
+// switch-ins after a faint cannot be cancelled and fall back to the first
+// Pokemon still able to battle.
+func (b *battleSession) sendOut(forced bool) error {
+	index, err := choosePlayerPokemon(b.reader, &b.player)
+	if err != nil {
+		if !forced || !errors.Is(err, errSelectionCancelled) {
+			return err
+		}
+		index = -1
+		for i, member := range b.player.team {
+			if member.current > 0 {
+				index = i
+				break
 			}
-			if status != "" {
-				wildStatus = status
-				fmt.Printf("Wild %s is now %s.\n", wildBattle.pokemon.name, wildStatus)
+		}
+		if index < 0 {
+			return errNoPokemon
+		}
+	}
+
+	member := &b.player.team[index]
+	if !member.entered {
+		member.commitHP()
+		if err := applyRestXP(b.c, &member.pokemon); err != nil {
+			return err
+		}
+		member.refreshHP()
+		member.entered = true
+		b.rec.addParticipant(sidePlayer, member.pokemon)
+	}
+	b.player.bringIn(index)
+	b.rec.emit(battleEvent{
+		Kind:    eventSwitch,
+		Side:    sidePlayer,
+		Pokemon: member.pokemon.name,
+		HP:      member.current,
+		Message: fmt.Sprintf("Go! %s!", member.pokemon.name),
+	})
+	return nil
+}
+
+// sendOutFoe brings in the trainer's team member at index.
+func (b *battleSession) sendOutFoe(index int) {
+	b.foe.bringIn(index)
+	member := b.foe.current()
+	if !member.entered {
+		member.entered = true
+		b.rec.addParticipant(b.foe.name, member.pokemon)
+	}
+	b.rec.emit(battleEvent{
+		Kind:    eventSwitch,
+		Side:    b.foe.name,
+		Pokemon: member.pokemon.name,
+		HP:      member.current,
+		Message: fmt.Sprintf("%s sent out %s!", b.trainer.DisplayName, member.pokemon.name),
+	})
+}
+
+func (b *battleSession) chooseFoeTurn() battleTurn {
+	return b.ai.chooseTurn(b.r, &b.foe, *b.player.current())
+}
+
+func (b *battleSession) foeSwitch(index int) {
+	b.rec.action(b.foe.name, actionSwitch, b.foe.team[index].pokemon.name)
+	b.rec.say(eventSwitch, "%s withdrew %s!", b.trainer.DisplayName, b.foe.current().pokemon.name)
+	b.sendOutFoe(index)
+}
+
+func (b *battleSession) fightTurn() (turnOutcome, error) {
+	active := b.player.current()
+	move := chooseMove(b.reader, active.pokemon)
+	turn := b.chooseFoeTurn()
+	b.rec.action(sidePlayer, actionFight, move.name)
+	if turn.switchTo >= 0 {
+		b.foeSwitch(turn.switchTo)
+		b.rec.emit(resolveAttack(b.r, active, b.foe.current(), move)...)
+		return usedTurn(b.checkFaints())
+	}
+
+	foe := b.foe.current()
+	b.rec.action(b.foe.name, actionFight, turn.move.name)
+	if decideFirst(b.r, move, turn.move, *active, *foe) {
+		b.rec.emit(resolveAttack(b.r, active, foe, move)...)
+		if foe.current > 0 && active.current > 0 {
+			b.rec.emit(resolveAttack(b.r, foe, active, turn.move)...)
+		}
+	} else {
+		b.rec.emit(resolveAttack(b.r, foe, active, turn.move)...)
+		if active.current > 0 {
+			b.rec.emit(resolveAttack(b.r, active, foe, move)...)
+		}
+	}
+	return usedTurn(b.checkFaints())
+}
+
+func (b *battleSession) catchTurn() (turnOutcome, error) {
+	if b.trainer != nil {
+		fmt.Println("You can't catch another trainer's Pokemon!")
+		return turnFree, nil
+	}
+	wild := b.foe.current()
+	caught, thrown, err := attemptCatchInBattle(b.reader, b.r, b.c, wild)
+	if err != nil {
+		return turnOver, err
+	}
+	if !thrown {
+		return turnFree, nil
+	}
+	b.rec.action(sidePlayer, actionCatch, "")
+	if caught {
+		b.rec.result = resultCaught
+		b.rec.say(eventCatch, "%s was caught!", wild.pokemon.name)
+		wild.commitHP()
+		caughtPokemon := wild.pokemon
+		caughtPokemon.dateCaught = time.Now()
+		catchIntoPokedex(b.c, caughtPokemon)
+		if active := b.player.current(); active != nil && active.current > 0 {
+			active.commitHP()
+			err = awardCaptureXP(b.c, &active.pokemon, wild.pokemon.baseExperience)
+			active.refreshHP()
+			if err == nil {
+				b.syncParty()
 			}
+		} else {
+			saveUserData(b.c)
 		}
+		grantRandomSupplies(b.c, "Catch")
+		return turnOver, err
+	}
+	b.rec.say(eventCatch, "%s escaped the ball!", wild.pokemon.name)
+	return usedTurn(b.foeAttack())
+}
 
-		round++
+// runTurn tries to flee a wild battle. A failed attempt costs the turn;
+// being trapped doesn't, the player just has to pick something else.
+func (b *battleSession) runTurn() (turnOutcome, error) {
+	if b.trainer != nil {
+		fmt.Println("There's no running from a trainer battle!")
+		return turnFree, nil
 	}
+	active := b.player.current()
+	if active == nil {
+		b.rec.action(sidePlayer, actionRun, "")
+		b.rec.result = resultRan
+		b.rec.say(eventRun, "You ran away.")
+		b.syncParty()
+		return turnOver, nil
+	}
+	escaped, blocked, reason := attemptEscape(b.r, active, b.foe.current(), b.escapes+1)
+	if blocked {
+		fmt.Println(reason)
+		return turnFree, nil
+	}
+	b.escapes++
+	b.rec.action(sidePlayer, actionRun, "")
+	if !escaped {
+		b.rec.say(eventRun, "%s", reason)
+		return usedTurn(b.foeAttack())
+	}
+	b.rec.result = resultRan
+	b.rec.say(eventRun, "You ran away.")
+	b.syncParty()
+	return turnOver, nil
 }
 
-func choosePlayerPokemon(c *config, reader *bufio.Reader) (pokemonSelection, error) {
-	if len(c.Pokedex) == 0 {
-		return pokemonSelection{}, errNoPokemon
+func (b *battleSession) itemTurn() (turnOutcome, error) {
+	items := b.c.Bag.itemsIn(itemCategoryHealing, itemCategoryStatusCure, itemCategoryRevival, itemCategoryStatBoost)
+	if len(items) == 0 {
+		fmt.Println("No usable items in your bag")
+		return turnFree, nil
+	}
+	fmt.Println("Choose an item:")
+	for i, name := range items {
+		fmt.Printf("%d) %s (x%d)\n", i+1, itemDisplayName(name), b.c.Bag.count(name))
+	}
+	choice, cancelled, err := promptChoice(b.reader, "Item > ", len(items))
+	if err != nil {
+		return turnOver, err
+	}
+	if cancelled {
+		return turnFree, nil
+	}
+
+	item := lookupItem(items[choice-1])
+	events, err := b.applyBattleItem(item)
+	if err != nil {
+		if errors.Is(err, errSelectionCancelled) {
+			return turnFree, nil
+		}
+		if errors.Is(err, errNoEffect) {
+			fmt.Println("It won't have any effect.")
+			return turnFree, nil
+		}
+		return turnOver, err
 	}
+	b.c.Bag.take(item.name)
+	saveUserData(b.c)
+	b.rec.action(sidePlayer, actionItem, item.name)
+	b.rec.say(eventItem, "You used %s.", itemDisplayName(item.name))
+	b.rec.emit(events...)
+	return usedTurn(b.foeAttack())
+}
 
-	keys := make([]string, 0, len(c.Pokedex))
-	for key := range c.Pokedex {
-		keys = append(keys, key)
+func (b *battleSession) applyBattleItem(item itemDefinition) ([]battleEvent, error) {
+	if item.category == itemCategoryRevival {
+		index, err := chooseFaintedMember(b.reader, &b.player)
+		if err != nil {
+			return nil, err
+		}
+		target := &b.player.team[index]
+		reviveBattlePokemon(target, item)
+		return []battleEvent{{
+			Kind:    eventHeal,
+			Side:    sidePlayer,
+			Pokemon: target.pokemon.name,
+			HP:      target.current,
+			Message: fmt.Sprintf("%s was revived!", target.pokemon.name),
+		}}, nil
 	}
-	sort.Strings(keys)
 
-	selections := make([]pokemonSelection, 0)
-	for _, key := range keys {
-		entries := c.Pokedex[key]
-		for index, entry := range entries {
-			label := fmt.Sprintf("%s (Lv %d)", entry.name, entry.level)
-			selections = append(selections, pokemonSelection{key: key, index: index, label: label})
+	active := b.player.current()
+	if active == nil {
+		fmt.Println("Send out a Pokemon first")
+		return nil, errSelectionCancelled
+	}
+	switch item.category {
+	case itemCategoryHealing, itemCategoryStatusCure:
+		healed := 0
+		if item.fullHeal {
+			healed = healBattlePokemon(active, active.max)
+		} else {
+			healed = healBattlePokemon(active, item.heal)
+		}
+		cured := active.status != statusNone && item.curesStatus(active.status)
+		if healed == 0 && !cured {
+			return nil, errNoEffect
+		}
+		events := make([]battleEvent, 0, 2)
+		if healed > 0 {
+			events = append(events, battleEvent{
+				Kind:    eventHeal,
+				Side:    sidePlayer,
+				Pokemon: active.pokemon.name,
+				HP:      active.current,
+				Message: fmt.Sprintf("%s recovered %d HP!", active.pokemon.name, healed),
+			})
 		}
+		if cured {
+			events = append(events, battleEvent{
+				Kind:    eventStatus,
+				Side:    sidePlayer,
+				Pokemon: active.pokemon.name,
+				HP:      active.current,
+				Message: fmt.Sprintf("%s is no longer %s.", active.pokemon.name, active.status),
+			})
+			active.status = statusNone
+		}
+		return events, nil
+	case itemCategoryStatBoost:
+		return []battleEvent{applyStatChange(active, item.stat, 1)}, nil
 	}
+	return nil, errNoEffect
+}
 
-	if len(selections) == 0 {
-		return pokemonSelection{}, errNoPokemon
+func (b *battleSession) switchTurn() (turnOutcome, error) {
+	if b.player.current() != nil && b.player.remaining() <= 1 {
+		fmt.Println("No other Pokemon can battle!")
+		return turnFree, nil
+	}
+	hadActive := b.player.current() != nil
+	if err := b.sendOut(false); err != nil {
+		if errors.Is(err, errSelectionCancelled) {
+			return turnFree, nil
+		}
+		return turnOver, err
 	}
+	b.rec.action(sidePlayer, actionSwitch, b.player.current().pokemon.name)
+	if !hadActive {
+		return turnFree, nil
+	}
+	return usedTurn(b.foeAttack())
+}
 
+func (b *battleSession) foeAttack() (bool, error) {
+	active := b.player.current()
+	if active == nil {
+		return false, nil
+	}
+	turn := b.chooseFoeTurn()
+	if turn.switchTo >= 0 {
+		b.foeSwitch(turn.switchTo)
+		return false, nil
+	}
+	b.rec.action(b.foe.name, actionFight, turn.move.name)
+	b.rec.emit(resolveAttack(b.r, b.foe.current(), active, turn.move)...)
+	return b.checkFaints()
+}
+
+// endRound hurts burned and poisoned Pokemon and lets held items act once
+// both sides have moved.
+func (b *battleSession) endRound() (bool, error) {
+	if active := b.player.current(); active != nil {
+		b.rec.emit(endOfTurn(b.r, active)...)
+	}
+	b.rec.emit(endOfTurn(b.r, b.foe.current())...)
+	return b.checkFaints()
+}
+
+func (b *battleSession) checkFaints() (bool, error) {
+	if foe := b.foe.current(); foe.current <= 0 {
+		b.rec.say(eventFaint, "%s fainted!", foe.displayName())
+		if active := b.player.current(); active != nil && active.current > 0 {
+			active.commitHP()
+			gainEffort(&active.pokemon, foe.pokemon.effortYield)
+			err := awardBattleXP(b.c, &active.pokemon, foe.pokemon.baseExperience)
+			active.refreshHP()
+			if err != nil {
+				return true, err
+			}
+		}
+		if b.foe.remaining() == 0 {
+			return true, b.win()
+		}
+		b.sendOutFoe(b.ai.chooseReplacement(&b.foe, b.player.current()))
+	}
+	active := b.player.current()
+	if active == nil || active.current > 0 {
+		return false, nil
+	}
+	b.rec.say(eventFaint, "%s fainted!", active.pokemon.name)
+	changeFriendship(&active.pokemon, friendshipFaint)
+	if b.player.remaining() == 0 {
+		b.rec.result = resultLoss
+		b.rec.say(eventFaint, "You have no more Pokemon that can battle!")
+		b.syncParty()
+		return true, nil
+	}
+	if err := b.sendOut(true); err != nil {
+		return true, err
+	}
+	return false, nil
+}
+
+func (b *battleSession) win() error {
+	b.rec.result = resultWin
+	if b.trainer != nil {
+		b.rec.say(eventVictory, "You defeated %s!", b.trainer.DisplayName)
+		b.c.Money += b.trainer.Prize
+		recordTrainerDefeat(b.c, b.trainer.Name, time.Now())
+		b.rec.say(eventVictory, "You got $%d for winning!", b.trainer.Prize)
+	}
+	for i := range b.player.team {
+		if member := &b.player.team[i]; member.entered && member.current > 0 {
+			changeFriendship(&member.pokemon, friendshipBattle)
+		}
+	}
+	b.syncParty()
+	grantRandomSupplies(b.c, "Battle win")
+	return nil
+}
+
+func (b *battleSession) syncParty() {
+	for i := range b.player.team {
+		member := &b.player.team[i]
+		member.commitHP()
+		syncPlayerPokemon(b.c, member.pokemon)
+	}
+	saveUserData(b.c)
+}
+
+func chooseFaintedMember(reader *bufio.Reader, side *battleSide) (int, error) {
+	fainted := make([]int, 0, len(side.team))
+	for i, member := range side.team {
+		if member.current <= 0 {
+			fainted = append(fainted, i)
+		}
+	}
+	if len(fainted) == 0 {
+		return 0, errNoEffect
+	}
+	fmt.Println("Choose a fainted Pokemon:")
+	for i, index := range fainted {
+		fmt.Printf("%d) %s\n", i+1, pokemonLabel(side.team[index].pokemon))
+	}
+	choice, cancelled, err := promptChoice(reader, "Selection > ", len(fainted))
+	if err != nil {
+		return 0, err
+	}
+	if cancelled {
+		return 0, errSelectionCancelled
+	}
+	return fainted[choice-1], nil
+}
+
+func choosePlayerPokemon(reader *bufio.Reader, side *battleSide) (int, error) {
+	eligible := make([]int, 0, len(side.team))
 	fmt.Println("Choose your Pokemon:")
-	for i, selection := range selections {
-		fmt.Printf("%d) %s\n", i+1, selection.label)
+	for i, member := range side.team {
+		label := pokemonLabel(member.pokemon)
+		switch {
+		case i == side.active:
+			fmt.Printf("-  %s (in battle)\n", label)
+		case member.current <= 0:
+			fmt.Printf("-  %s (fainted)\n", label)
+		default:
+			eligible = append(eligible, i)
+			fmt.Printf("%d) %s %s\n", len(eligible), label, formatHP(member.current, member.max))
+		}
+	}
+	if len(eligible) == 0 {
+		return 0, errNoPokemon
 	}
 
-	choice, cancelled, err := promptChoice(reader, "Selection > ", len(selections))
+	choice, cancelled, err := promptChoice(reader, "Selection > ", len(eligible))
 	if err != nil {
-		return pokemonSelection{}, err
+		return 0, err
 	}
 	if cancelled {
-		return pokemonSelection{}, errSelectionCancelled
+		return 0, errSelectionCancelled
 	}
-	return selections[choice-1], nil
+	return eligible[choice-1], nil
 }
 
 func chooseMove(reader *bufio.Reader, pokemon Pokemon) PokemonMove {
 	moves := availableMoves(pokemon)
+	if !hasUsableMove(moves) {
+		fmt.Printf("%s has no moves left!\n", pokemon.name)
+		return struggleMove()
+	}
 	fmt.Println("Choose a move:")
 	for i, move := range moves {
-		fmt.Printf("%d) %s (power %d, acc %d, prio %d, type %s)\n", i+1, move.name, move.power, move.accuracy, move.priority, move.moveType)
+		fmt.Printf("%d) %s (PP %s, power %d, acc %d, prio %d, type %s)\n", i+1, move.name, formatPP(move), move.power, move.accuracy, move.priority, move.moveType)
 	}
-	choice, cancelled, err := promptChoice(reader, "Move > ", len(moves))
-	if err != nil || cancelled {
-		return moves[0]
+	for {
+		choice, cancelled, err := promptChoice(reader, "Move > ", len(moves))
+		if err != nil || cancelled {
+			return firstUsableMove(moves)
+		}
+		if moveUsable(moves[choice-1]) {
+			return moves[choice-1]
+		}
+		fmt.Println("No PP left for that move!")
 	}
-	return moves[choice-1]
 }
 
-func chooseWildMove(pokemon Pokemon) PokemonMove {
-	moves := availableMoves(pokemon)
-	return moves[rng.Intn(len(moves))]
+func chooseWildMove(r *rand.Rand, pokemon Pokemon) PokemonMove {
+	moves := make([]PokemonMove, 0, 4)
+	for _, move := range availableMoves(pokemon) {
+		if moveUsable(move) {
+			moves = append(moves, move)
+		}
+	}
+	if len(moves) == 0 {
+		return struggleMove()
+	}
+	return moves[r.Intn(len(moves))]
+}
+
+func firstUsableMove(moves []PokemonMove) PokemonMove {
+	for _, move := range moves {
+		if moveUsable(move) {
+			return move
+		}
+	}
+	return struggleMove()
+}
+
+func formatPP(move PokemonMove) string {
+	if move.maxPP <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d/%d", move.pp, move.maxPP)
 }
 
 func availableMoves(pokemon Pokemon) []PokemonMove {
@@ -266,65 +790,167 @@ func availableMoves(pokemon Pokemon) []PokemonMove {
 	return pokemon.moves
 }
 
-func decideFirst(playerMove, wildMove PokemonMove, player Pokemon, wild Pokemon) bool {
-	if playerMove.priority > wildMove.priority {
+func decideFirst(r *rand.Rand, playerMove, foeMove PokemonMove, player, foe battlePokemon) bool {
+	if playerMove.priority > foeMove.priority {
 		return true
 	}
-	if playerMove.priority < wildMove.priority {
+	if playerMove.priority < foeMove.priority {
 		return false
 	}
-	playerSpeed := player.stats["speed"]
-	wildSpeed := wild.stats["speed"]
-	if playerSpeed == wildSpeed {
-		return rng.Intn(2) == 0
+	playerSpeed := effectiveStat(player, "speed")
+	foeSpeed := effectiveStat(foe, "speed")
+	if playerSpeed == foeSpeed {
+		return r.Intn(2) == 0
+	}
+	return playerSpeed > foeSpeed
+}
+
+// resolveAttack has attacker use move on defender, unless its status keeps
+// it from moving.
+func resolveAttack(r *rand.Rand, attacker, defender *battlePokemon, move PokemonMove) []battleEvent {
+	events, stopped := statusBeforeMove(r, attacker)
+	if stopped {
+		return events
 	}
-	return playerSpeed > wildSpeed
+	return append(events, useMove(r, attacker, defender, move)...)
 }
 
-func resolveAttack(attacker, defender *battlePokemon, move PokemonMove, isPlayer bool) {
+func useMove(r *rand.Rand, attacker, defender *battlePokemon, move PokemonMove) []battleEvent {
+	event := battleEvent{
+		Kind:    eventAttack,
+		Side:    attacker.side,
+		Target:  defender.side,
+		Pokemon: attacker.pokemon.name,
+		Move:    move.name,
+	}
+	spendPP(&attacker.pokemon, move.name)
+
+	name := attacker.displayName()
+	selfTargeted := isStatusMove(move) && moveTargetsUser(move)
 	accuracy := move.accuracy
 	if accuracy <= 0 {
 		accuracy = 100
 	}
-	if rng.Intn(100) >= accuracy {
-		if isPlayer {
-			fmt.Printf("%s used %s but missed!\n", attacker.pokemon.name, move.name)
-		} else {
-			fmt.Printf("Wild %s used %s but missed!\n", attacker.pokemon.name, move.name)
+	hitChance := float64(accuracy) * accuracyStageMultiplier(attacker.stages["accuracy"]-defender.stages["evasion"])
+	if !selfTargeted && r.Float64()*100 >= hitChance {
+		event.Kind = eventMiss
+		event.HP = defender.current
+		event.Message = fmt.Sprintf("%s used %s but missed!", name, move.name)
+		return []battleEvent{event}
+	}
+
+	if isStatusMove(move) {
+		event.HP = defender.current
+		event.Message = fmt.Sprintf("%s used %s!", name, move.name)
+		events := append([]battleEvent{event}, applyMoveStatChanges(attacker, defender, move)...)
+		events = append(events, applyTrap(r, attacker, defender, move)...)
+		if len(events) == 1 {
+			events[0].Message += " But nothing happened."
 		}
-		return
+		return events
 	}
 
-	damage := calculateDamage(attacker.pokemon, defender.pokemon, move)
+	effectiveness := typeEffectiveness(move.moveType, defender.pokemon.types)
+	if effectiveness == 0 {
+		event.HP = defender.current
+		event.Message = fmt.Sprintf("%s used %s! It doesn't affect %s...", name, move.name, defender.displayName())
+		return []battleEvent{event}
+	}
+
+	damage := calculateDamage(*attacker, *defender, move)
 	defender.current -= damage
 	if defender.current < 0 {
 		defender.current = 0
 	}
-	if isPlayer {
-		fmt.Printf("%s used %s for %d damage!\n", attacker.pokemon.name, move.name, damage)
-	} else {
-		fmt.Printf("Wild %s used %s for %d damage!\n", attacker.pokemon.name, move.name, damage)
+	event.Damage = damage
+	event.HP = defender.current
+	event.Message = fmt.Sprintf("%s used %s for %d damage!%s", name, move.name, damage, effectivenessMessage(effectiveness))
+	events := []battleEvent{event}
+
+	if len(move.statChanges) > 0 && (defender.current > 0 || statChangesHitUser(move)) && r.Intn(100) < move.statChance {
+		events = append(events, applyMoveStatChanges(attacker, defender, move)...)
 	}
+	events = append(events, applyTrap(r, attacker, defender, move)...)
+
+	if move.name == struggleMoveName {
+		recoil := struggleRecoil(attacker)
+		attacker.current = max(0, attacker.current-recoil)
+		events = append(events, battleEvent{
+			Kind:    eventRecoil,
+			Side:    event.Side,
+			Pokemon: attacker.pokemon.name,
+			Damage:  recoil,
+			HP:      attacker.current,
+			Message: fmt.Sprintf("%s is hit with %d recoil!", name, recoil),
+		})
+	}
+	return events
 }
 
-func calculateDamage(attacker, defender Pokemon, move PokemonMove) int {
+func applyMoveStatChanges(attacker, defender *battlePokemon, move PokemonMove) []battleEvent {
+	target := defender
+	if statChangesHitUser(move) {
+		target = attacker
+	}
+	events := make([]battleEvent, 0, len(move.statChanges))
+	for _, change := range move.statChanges {
+		events = append(events, applyStatChange(target, change.stat, change.change))
+	}
+	return events
+}
+
+// calculateDamage includes STAB and type effectiveness; immune defenders
+// and status moves deal no damage, and a burn halves physical damage.
+func calculateDamage(attacker, defender battlePokemon, move PokemonMove) int {
+	if isStatusMove(move) {
+		return 0
+	}
+	effectiveness := typeEffectiveness(move.moveType, defender.pokemon.types)
+	if effectiveness == 0 {
+		return 0
+	}
 	power := move.power
+	if friendshipPower, ok := friendshipMovePower(attacker.pokemon, move); ok {
+		power = friendshipPower
+	}
 	if power <= 0 {
 		power = 40
 	}
-	level := attacker.level
+	level := attacker.pokemon.level
 	if level <= 0 {
 		level = 5
 	}
-	attack := attacker.stats["attack"]
-	defense := defender.stats["defense"]
+	attackStat, defenseStat := "attack", "defense"
+	if move.damageClass == "special" {
+		attackStat, defenseStat = "special-attack", "special-defense"
+	}
+	attack := effectiveStat(attacker, attackStat)
+	defense := effectiveStat(defender, defenseStat)
 	base := (power / 3) + (level / 2)
 	bonus := (attack / 8) - (defense / 16)
-	damage := base + bonus
-	return max(1, damage)
+	damage := float64(max(1, base+bonus)) * stabMultiplier(move.moveType, attacker.pokemon.types) * effectiveness
+	if attacker.status == statusBurn && move.damageClass != "special" {
+		damage /= 2
+	}
+	return max(1, int(math.Round(damage)))
+}
+
+func newBattlePokemon(pokemon Pokemon) battlePokemon {
+	pokemon.moves = append([]PokemonMove(nil), pokemon.moves...)
+	battle := battlePokemon{pokemon: pokemon, stages: make(statStages)}
+	battle.refreshHP()
+	if battle.status == statusSleep {
+		battle.sleepTurns = 1
+	}
+	return battle
 }
 
+// maxHP is the real HP stat; Pokemon without base stats fall back to a
+// simple level bonus on top of their HP.
 func maxHP(pokemon Pokemon) int {
+	if len(pokemon.baseStats) > 0 {
+		return max(1, pokemon.stats["hp"])
+	}
 	hp := pokemon.stats["hp"]
 	if hp <= 0 {
 		hp = 50
@@ -336,54 +962,40 @@ func maxHP(pokemon Pokemon) int {
 	return hp + (level * 2)
 }
 
-func attemptCatchInBattle(reader *bufio.Reader, c *config, wild *battlePokemon, status string) (bool, error) {
-	ballChoice, cancelled, err := promptChoice(
-		reader,
-		fmt.Sprintf(
-			"Choose ball: 1) Pokeball (x%d) 2) Great Ball (x%d) 3) Ultra Ball (x%d) > ",
-			c.Inventory.Pokeball,
-			c.Inventory.GreatBall,
-			c.Inventory.UltraBall,
-		),
-		3,
-	)
+// attemptCatchInBattle throws a ball from the bag. thrown is false when the
+// player backed out or had no balls, so the turn is not used up.
+func attemptCatchInBattle(reader *bufio.Reader, r *rand.Rand, c *config, wild *battlePokemon) (caught bool, thrown bool, err error) {
+	balls := make([]string, 0, len(ballOrder))
+	for _, name := range ballOrder {
+		if c.Bag.count(name) > 0 {
+			balls = append(balls, name)
+		}
+	}
+	if len(balls) == 0 {
+		fmt.Println("No Poke Balls left")
+		return false, false, nil
+	}
+	labels := make([]string, 0, len(balls))
+	for i, name := range balls {
+		labels = append(labels, fmt.Sprintf("%d) %s (x%d)", i+1, itemDisplayName(name), c.Bag.count(name)))
+	}
+	ballChoice, cancelled, err := promptChoice(reader, fmt.Sprintf("Choose ball: %s > ", strings.Join(labels, " ")), len(balls))
 	if err != nil {
-		return false, err
+		return false, false, err
 	}
 	if cancelled {
-		return false, nil
+		return false, false, nil
 	}
 
-	ballFactor := 1.0
-	switch ballChoice {
-	case 1:
-		if c.Inventory.Pokeball <= 0 {
-			fmt.Println("No Pokeballs left")
-			return false, nil
-		}
-		c.Inventory.Pokeball--
-		saveUserData(c)
-		ballFactor = 0.7
-	case 2:
-		if c.Inventory.GreatBall <= 0 {
-			fmt.Println("No Great Balls left")
-			return false, nil
-		}
-		c.Inventory.GreatBall--
-		saveUserData(c)
-		ballFactor = 1.0
-	case 3:
-		if c.Inventory.UltraBall <= 0 {
-			fmt.Println("No Ultra Balls left")
-			return false, nil
-		}
-		c.Inventory.UltraBall--
-		saveUserData(c)
-		ballFactor = 1.15
+	ball := lookupItem(balls[ballChoice-1])
+	c.Bag.take(ball.name)
+	saveUserData(c)
+	if ball.guaranteed {
+		return true, true, nil
 	}
 
 	statusFactor := 1.0
-	if status == statusSleep || status == statusParalysis {
+	if wild.status != statusNone {
 		statusFactor = 1.25
 	}
 	if wild.max <= 0 {
@@ -392,34 +1004,10 @@ func attemptCatchInBattle(reader *bufio.Reader, c *config, wild *battlePokemon,
 	hpRatio := float64(wild.current) / float64(wild.max)
 	hpFactor := 0.3 + (0.7 * (1.0 - hpRatio))
 	base := catchProb(wild.pokemon.baseExperience)
-	chance := base * ballFactor * statusFactor * hpFactor
+	chance := base * ball.ballFactor * statusFactor * hpFactor
 	chance = math.Min(0.95, math.Max(0.02, chance))
 
-	return rng.Float64() < chance, nil
-}
-
-func applyPotion(reader *bufio.Reader, c *config) (string, error) {
-	if c.Inventory.Potion <= 0 {
-		fmt.Println("No potions left")
-		return "", nil
-	}
-	choice, cancelled, err := promptChoice(
-		reader,
-		fmt.Sprintf("Choose status (Potion x%d): 1) Sleep 2) Paralysis > ", c.Inventory.Potion),
-		2,
-	)
-	if err != nil {
-		return "", err
-	}
-	if cancelled {
-		return "", nil
-	}
-	c.Inventory.Potion--
-	saveUserData(c)
-	if choice == 1 {
-		return statusSleep, nil
-	}
-	return statusParalysis, nil
+	return r.Float64() < chance, true, nil
 }
 
 func awardBattleXP(c *config, pokemon *Pokemon, baseXP int) error {
@@ -437,14 +1025,15 @@ func awardCaptureXP(c *config, pokemon *Pokemon, baseXP int) error {
 	return applyExperience(c, pokemon, baseXP, true)
 }
 
-func syncPlayerPokemon(c *config, selection pokemonSelection, updated Pokemon) {
+func syncPlayerPokemon(c *config, updated Pokemon) {
 	if c == nil {
 		return
 	}
-	entries, exists := c.Pokedex[selection.key]
-	if !exists || selection.index >= len(entries) {
+	selection, exists := findOwnedPokemon(c, updated.uid)
+	if !exists {
 		return
 	}
+	entries := c.Pokedex[selection.key]
 	if updated.name == selection.key {
 		entries[selection.index] = updated
 		c.Pokedex[selection.key] = entries
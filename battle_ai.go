@@ -0,0 +1,289 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+)
+
+// BattleAI decides what an AI-controlled side does in battle.
+type BattleAI interface {
+	name() string
+	// chooseTurn picks a move for the active Pokemon or a switch.
+	chooseTurn(r *rand.Rand, side *battleSide, opponent battlePokemon) battleTurn
+	// chooseReplacement picks the team member to send out when the active
+	// one can no longer battle. opponent is nil before the first send-out.
+	chooseReplacement(side *battleSide, opponent *battlePokemon) int
+}
+
+const (
+	aiRandom    = "random"
+	aiGreedy    = "greedy"
+	aiTypeAware = "type-aware"
+	aiMinimax   = "minimax"
+)
+
+var battleAINames = []string{aiRandom, aiGreedy, aiTypeAware, aiMinimax}
+
+func lookupBattleAI(name string) (BattleAI, bool) {
+	switch name {
+	case aiRandom:
+		return randomAI{}, true
+	case aiGreedy:
+		return greedyAI{}, true
+	case aiTypeAware:
+		return typeAwareAI{}, true
+	case aiMinimax:
+		return minimaxAI{depth: 2}, true
+	}
+	return nil, false
+}
+
+// trainerAI is the trainer's own strategy when the data names one, and
+// otherwise the strategy for its difficulty.
+func trainerAI(trainer npcTrainer) BattleAI {
+	if ai, exists := lookupBattleAI(trainer.AI); exists {
+		return ai
+	}
+	switch trainer.Difficulty {
+	case difficultyMedium:
+		return greedyAI{}
+	case difficultyHard:
+		return typeAwareAI{}
+	}
+	return randomAI{}
+}
+
+// randomAI picks uniformly from the usable moves, like a wild Pokemon.
+type randomAI struct{}
+
+func (randomAI) name() string { return aiRandom }
+
+func (randomAI) chooseTurn(r *rand.Rand, side *battleSide, opponent battlePokemon) battleTurn {
+	return battleTurn{move: chooseWildMove(r, side.current().pokemon), switchTo: -1}
+}
+
+func (randomAI) chooseReplacement(side *battleSide, opponent *battlePokemon) int {
+	return nextAbleMember(side)
+}
+
+// greedyAI always picks the move with the best expected damage.
+type greedyAI struct{}
+
+func (greedyAI) name() string { return aiGreedy }
+
+func (greedyAI) chooseTurn(r *rand.Rand, side *battleSide, opponent battlePokemon) battleTurn {
+	return battleTurn{move: bestDamageMove(r, *side.current(), opponent), switchTo: -1}
+}
+
+func (greedyAI) chooseReplacement(side *battleSide, opponent *battlePokemon) int {
+	return nextAbleMember(side)
+}
+
+// typeAwareAI attacks like greedyAI but switches out of bad type matchups
+// and sends out the best matchup after a faint.
+type typeAwareAI struct{}
+
+func (typeAwareAI) name() string { return aiTypeAware }
+
+func (typeAwareAI) chooseTurn(r *rand.Rand, side *battleSide, opponent battlePokemon) battleTurn {
+	if index, ok := betterMatchup(side, opponent); ok {
+		return battleTurn{switchTo: index}
+	}
+	return battleTurn{move: bestDamageMove(r, *side.current(), opponent), switchTo: -1}
+}
+
+func (typeAwareAI) chooseReplacement(side *battleSide, opponent *battlePokemon) int {
+	return bestMatchupMember(side, opponent)
+}
+
+// minimaxAI looks depth turns ahead, assuming the opponent answers each
+// move with its most damaging reply and every hit does its expected damage.
+type minimaxAI struct {
+	depth int
+}
+
+func (minimaxAI) name() string { return aiMinimax }
+
+func (ai minimaxAI) chooseTurn(r *rand.Rand, side *battleSide, opponent battlePokemon) battleTurn {
+	active := *side.current()
+	best, bestScore := struggleMove(), math.Inf(-1)
+	for _, move := range usableMoves(active.pokemon) {
+		if score := ai.worstReply(active, opponent, move, ai.depth); score > bestScore {
+			best, bestScore = move, score
+		}
+	}
+	return battleTurn{move: best, switchTo: -1}
+}
+
+func (minimaxAI) chooseReplacement(side *battleSide, opponent *battlePokemon) int {
+	return bestMatchupMember(side, opponent)
+}
+
+func (ai minimaxAI) search(self, opponent battlePokemon, depth int) float64 {
+	if depth == 0 || self.current <= 0 || opponent.current <= 0 {
+		return hpBalance(self, opponent)
+	}
+	best := math.Inf(-1)
+	for _, move := range usableMoves(self.pokemon) {
+		best = max(best, ai.worstReply(self, opponent, move, depth))
+	}
+	return best
+}
+
+func (ai minimaxAI) worstReply(self, opponent battlePokemon, move PokemonMove, depth int) float64 {
+	worst := math.Inf(1)
+	for _, reply := range usableMoves(opponent.pokemon) {
+		nextSelf, nextOpponent := previewTurn(self, opponent, move, reply)
+		worst = min(worst, ai.search(nextSelf, nextOpponent, depth-1))
+	}
+	return worst
+}
+
+// previewTurn plays a turn without randomness: speed ties go to self and
+// each hit deals its expected damage.
+func previewTurn(self, opponent battlePokemon, move, reply PokemonMove) (battlePokemon, battlePokemon) {
+	hit := func(attacker battlePokemon, defender *battlePokemon, move PokemonMove) {
+		defender.current -= int(math.Round(expectedDamage(attacker, *defender, move)))
+	}
+	selfFirst := move.priority > reply.priority ||
+		(move.priority == reply.priority && effectiveStat(self, "speed") >= effectiveStat(opponent, "speed"))
+	if selfFirst {
+		hit(self, &opponent, move)
+		if opponent.current > 0 {
+			hit(opponent, &self, reply)
+		}
+	} else {
+		hit(opponent, &self, reply)
+		if self.current > 0 {
+			hit(self, &opponent, move)
+		}
+	}
+	return self, opponent
+}
+
+func hpBalance(self, opponent battlePokemon) float64 {
+	return hpFraction(self) - hpFraction(opponent)
+}
+
+func hpFraction(p battlePokemon) float64 {
+	if p.max <= 0 {
+		return 0
+	}
+	return float64(max(0, p.current)) / float64(p.max)
+}
+
+func usableMoves(pokemon Pokemon) []PokemonMove {
+	moves := make([]PokemonMove, 0, 4)
+	for _, move := range availableMoves(pokemon) {
+		if moveUsable(move) {
+			moves = append(moves, move)
+		}
+	}
+	if len(moves) == 0 {
+		return []PokemonMove{struggleMove()}
+	}
+	return moves
+}
+
+func nextAbleMember(side *battleSide) int {
+	for i, member := range side.team {
+		if member.current > 0 && i != side.active {
+			return i
+		}
+	}
+	return -1
+}
+
+func bestMatchupMember(side *battleSide, opponent *battlePokemon) int {
+	if opponent == nil {
+		return nextAbleMember(side)
+	}
+	best, bestScore := -1, 0.0
+	for i, member := range side.team {
+		if member.current <= 0 || i == side.active {
+			continue
+		}
+		if score := matchupScore(member, *opponent); best < 0 || score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
+
+// expectedDamage is the damage move deals to defender, capped at the
+// defender's remaining HP and weighted by the chance to hit.
+func expectedDamage(attacker, defender battlePokemon, move PokemonMove) float64 {
+	if isStatusMove(move) {
+		return 0
+	}
+	accuracy := move.accuracy
+	if accuracy <= 0 {
+		accuracy = 100
+	}
+	damage := min(calculateDamage(attacker, defender, move), defender.current)
+	return float64(damage) * float64(accuracy) / 100
+}
+
+func bestDamageMove(r *rand.Rand, attacker, defender battlePokemon) PokemonMove {
+	var best PokemonMove
+	bestScore := -1.0
+	for _, move := range availableMoves(attacker.pokemon) {
+		if !moveUsable(move) {
+			continue
+		}
+		if score := expectedDamage(attacker, defender, move); score > bestScore {
+			best, bestScore = move, score
+		}
+	}
+	if bestScore < 0 {
+		return struggleMove()
+	}
+	if bestScore == 0 {
+		return chooseWildMove(r, attacker.pokemon)
+	}
+	return best
+}
+
+// matchupScore rates member against opponent: the best multiplier its
+// damaging moves get (with STAB) minus the best multiplier the opponent's
+// types get against it.
+func matchupScore(member, opponent battlePokemon) float64 {
+	offense := 0.0
+	for _, move := range availableMoves(member.pokemon) {
+		if !moveUsable(move) || isStatusMove(move) {
+			continue
+		}
+		offense = max(offense, typeEffectiveness(move.moveType, opponent.pokemon.types)*stabMultiplier(move.moveType, member.pokemon.types))
+	}
+	defense := 0.0
+	for _, opponentType := range opponent.pokemon.types {
+		defense = max(defense, typeEffectiveness(opponentType, member.pokemon.types))
+	}
+	return offense - defense
+}
+
+// betterMatchup suggests a switch when the active Pokemon is weak to the
+// opponent and a healthy teammate fares clearly better.
+func betterMatchup(side *battleSide, opponent battlePokemon) (int, bool) {
+	active := side.current()
+	threatened := false
+	for _, opponentType := range opponent.pokemon.types {
+		if typeEffectiveness(opponentType, active.pokemon.types) > 1 {
+			threatened = true
+		}
+	}
+	if !threatened {
+		return 0, false
+	}
+	current := matchupScore(*active, opponent)
+	best, bestScore := -1, current+1
+	for i, member := range side.team {
+		if i == side.active || member.current*2 < member.max {
+			continue
+		}
+		if score := matchupScore(member, opponent); score >= bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best, best >= 0
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestGreedyAIPicksSuperEffectiveMove(t *testing.T) {
+	side := newBattleSide(sideTrainer, "", []Pokemon{{
+		name:  "wartortle",
+		level: 20,
+		types: []string{"water"},
+		moves: []PokemonMove{
+			{name: "tackle", power: 40, accuracy: 100, moveType: "normal", pp: 35, maxPP: 35},
+			{name: "water-gun", power: 40, accuracy: 100, moveType: "water", pp: 25, maxPP: 25},
+		},
+	}})
+	side.active = 0
+	opponent := newBattlePokemon(Pokemon{name: "charmander", level: 20, types: []string{"fire"}, currentHP: 60})
+	turn := greedyAI{}.chooseTurn(rand.New(rand.NewSource(1)), &side, opponent)
+	if turn.switchTo != -1 || turn.move.name != "water-gun" {
+		t.Fatalf("expected water-gun, got %+v", turn)
+	}
+}
+
+func TestTypeAwareAISwitchesOutOfBadMatchup(t *testing.T) {
+	side := newBattleSide(sideTrainer, "", []Pokemon{
+		{name: "charmander", level: 20, types: []string{"fire"}, moves: []PokemonMove{{name: "ember", power: 40, moveType: "fire"}}},
+		{name: "bulbasaur", level: 20, types: []string{"grass"}, moves: []PokemonMove{{name: "vine-whip", power: 45, moveType: "grass"}}},
+	})
+	side.active = 0
+	for i := range side.team {
+		side.team[i].current = side.team[i].max
+	}
+	opponent := newBattlePokemon(Pokemon{name: "squirtle", level: 20, types: []string{"water"}})
+	turn := typeAwareAI{}.chooseTurn(rand.New(rand.NewSource(1)), &side, opponent)
+	if turn.switchTo != 1 {
+		t.Fatalf("expected a switch to bulbasaur, got %+v", turn)
+	}
+}
+
+func TestTrainerAIFollowsDataThenDifficulty(t *testing.T) {
+	if got := trainerAI(npcTrainer{Difficulty: difficultyHard}).name(); got != aiTypeAware {
+		t.Fatalf("expected %s for a hard trainer, got %s", aiTypeAware, got)
+	}
+	if got := trainerAI(npcTrainer{Difficulty: difficultyEasy, AI: aiMinimax}).name(); got != aiMinimax {
+		t.Fatalf("expected the trainer's own AI, got %s", got)
+	}
+}
+
+func TestHarnessGreedyBeatsRandom(t *testing.T) {
+	team := []Pokemon{{
+		name:      "rattata",
+		level:     20,
+		types:     []string{"normal"},
+		stats:     map[string]int{"hp": 30, "attack": 56, "defense": 35, "speed": 72},
+		currentHP: 70,
+		moves: []PokemonMove{
+			{name: "tail-whip", damageClass: "status", accuracy: 100, statChanges: []moveStatChange{{stat: "defense", change: -1}}},
+			{name: "growl", damageClass: "status", accuracy: 100, statChanges: []moveStatChange{{stat: "attack", change: -1}}},
+			{name: "tackle", power: 40, accuracy: 100, moveType: "normal"},
+		},
+	}}
+	results := runAIHarness([]string{aiGreedy, aiRandom, aiMinimax}, team, 40, 7)
+	if len(results) != 3 {
+		t.Fatalf("expected three matchups, got %d", len(results))
+	}
+	if result := results[0]; result.wins <= result.losses {
+		t.Fatalf("expected greedy to beat random, got %+v", result)
+	}
+}
@@ -0,0 +1,353 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	sidePlayer  = "player"
+	sideWild    = "wild"
+	sideTrainer = "trainer"
+)
+
+const (
+	eventIntro      = "intro"
+	eventAttack     = "attack"
+	eventMiss       = "miss"
+	eventRecoil     = "recoil"
+	eventStatChange = "stat-change"
+	eventFaint      = "faint"
+	eventCatch      = "catch"
+	eventRun        = "run"
+	eventSwitch     = "switch"
+	eventHeal       = "heal"
+	eventItem       = "item"
+	eventStatus     = "status"
+	eventVictory    = "victory"
+)
+
+const (
+	actionFight  = "fight"
+	actionCatch  = "catch"
+	actionRun    = "run"
+	actionItem   = "item"
+	actionSwitch = "switch"
+)
+
+const (
+	resultWin       = "win"
+	resultLoss      = "loss"
+	resultCaught    = "caught"
+	resultRan       = "ran"
+	resultCancelled = "cancelled"
+	resultDraw      = "draw"
+)
+
+type battleRecord struct {
+	ID           int                 `json:"id"`
+	Trainer      string              `json:"trainer"`
+	Opponent     string              `json:"opponent,omitempty"`
+	StartedAt    time.Time           `json:"started_at"`
+	EndedAt      time.Time           `json:"ended_at"`
+	Seed         int64               `json:"seed"`
+	Result       string              `json:"result"`
+	Participants []battleParticipant `json:"participants"`
+	Actions      []battleAction      `json:"actions"`
+	Events       []battleEvent       `json:"events"`
+}
+
+type battleParticipant struct {
+	Side    string        `json:"side"`
+	Pokemon pokemonRecord `json:"pokemon"`
+}
+
+type battleAction struct {
+	Round  int    `json:"round"`
+	Side   string `json:"side"`
+	Action string `json:"action"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type battleEvent struct {
+	Round   int    `json:"round"`
+	Kind    string `json:"kind"`
+	Side    string `json:"side,omitempty"`
+	Target  string `json:"target,omitempty"`
+	Pokemon string `json:"pokemon,omitempty"`
+	Move    string `json:"move,omitempty"`
+	Damage  int    `json:"damage,omitempty"`
+	HP      int    `json:"hp"`
+	Message string `json:"message"`
+}
+
+type battleRecorder struct {
+	record battleRecord
+	round  int
+	result string
+}
+
+func newBattleRecorder(c *config, seed int64) *battleRecorder {
+	trainer := ""
+	if c != nil {
+		trainer = c.UserName
+	}
+	return &battleRecorder{
+		record: battleRecord{
+			Trainer:   trainer,
+			StartedAt: time.Now(),
+			Seed:      seed,
+		},
+	}
+}
+
+func (b *battleRecorder) addParticipant(side string, pokemon Pokemon) {
+	b.record.Participants = append(b.record.Participants, battleParticipant{
+		Side:    side,
+		Pokemon: pokemonToRecord(pokemon),
+	})
+}
+
+func (b *battleRecorder) action(side, action, detail string) {
+	b.record.Actions = append(b.record.Actions, battleAction{
+		Round:  b.round,
+		Side:   side,
+		Action: action,
+		Detail: detail,
+	})
+}
+
+func (b *battleRecorder) emit(events ...battleEvent) {
+	for _, event := range events {
+		event.Round = b.round
+		b.record.Events = append(b.record.Events, event)
+		fmt.Println(event.Message)
+	}
+}
+
+func (b *battleRecorder) say(kind string, format string, args ...any) {
+	b.emit(battleEvent{Kind: kind, Message: fmt.Sprintf(format, args...)})
+}
+
+func (b *battleRecorder) finish(c *config) error {
+	b.record.EndedAt = time.Now()
+	b.record.Result = b.result
+	if c == nil || c.StoragePath == "" {
+		return nil
+	}
+	return appendBattleRecord(battleLogPath(c.StoragePath), &b.record)
+}
+
+// mirrored is the record as the opponent in a trainer battle saw it: the
+// trainers, their sides and the result swap places.
+func (r battleRecord) mirrored() battleRecord {
+	mirror := r
+	mirror.ID = 0
+	mirror.Trainer, mirror.Opponent = r.Opponent, r.Trainer
+	switch r.Result {
+	case resultWin:
+		mirror.Result = resultLoss
+	case resultLoss:
+		mirror.Result = resultWin
+	}
+	mirror.Participants = make([]battleParticipant, len(r.Participants))
+	for i, participant := range r.Participants {
+		participant.Side = mirroredSide(participant.Side)
+		mirror.Participants[i] = participant
+	}
+	mirror.Actions = make([]battleAction, len(r.Actions))
+	for i, action := range r.Actions {
+		action.Side = mirroredSide(action.Side)
+		mirror.Actions[i] = action
+	}
+	mirror.Events = mirroredEvents(r.Events)
+	return mirror
+}
+
+// mirroredEvents are events as the trainer on the other side saw them.
+func mirroredEvents(events []battleEvent) []battleEvent {
+	mirror := make([]battleEvent, len(events))
+	for i, event := range events {
+		event.Side, event.Target = mirroredSide(event.Side), mirroredSide(event.Target)
+		mirror[i] = event
+	}
+	return mirror
+}
+
+func mirroredSide(side string) string {
+	switch side {
+	case sidePlayer:
+		return sideTrainer
+	case sideTrainer:
+		return sidePlayer
+	}
+	return side
+}
+
+func battleLogPath(storagePath string) string {
+	if storagePath == "" {
+		return ""
+	}
+	base := strings.TrimSuffix(filepath.Base(storagePath), filepath.Ext(storagePath))
+	return filepath.Join(filepath.Dir(storagePath), base+"-battles.jsonl")
+}
+
+func loadBattleRecords(path string) ([]battleRecord, error) {
+	if path == "" {
+		return nil, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	records := make([]battleRecord, 0)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var record battleRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func appendBattleRecord(path string, record *battleRecord) error {
+	if path == "" {
+		return nil
+	}
+	records, err := loadBattleRecords(path)
+	if err != nil {
+		return err
+	}
+	record.ID = 1
+	if len(records) > 0 {
+		record.ID = records[len(records)-1].ID + 1
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func findBattleRecord(records []battleRecord, id int) (battleRecord, error) {
+	for _, record := range records {
+		if record.ID == id {
+			return record, nil
+		}
+	}
+	return battleRecord{}, errors.New("No battle with that id")
+}
+
+func (r battleRecord) participant(side string) (pokemonRecord, bool) {
+	for _, participant := range r.Participants {
+		if participant.Side == side {
+			return participant.Pokemon, true
+		}
+	}
+	return pokemonRecord{}, false
+}
+
+func (r battleRecord) rounds() int {
+	rounds := 0
+	for _, event := range r.Events {
+		rounds = max(rounds, event.Round)
+	}
+	return rounds
+}
+
+func (r battleRecord) summary() string {
+	player := "(none)"
+	if poke, ok := r.participant(sidePlayer); ok {
+		player = fmt.Sprintf("%s (Lv %d)", poke.Name, poke.Level)
+	}
+	foe := "(unknown)"
+	if poke, ok := r.participant(sideWild); ok {
+		foe = fmt.Sprintf("wild %s (Lv %d)", poke.Name, poke.Level)
+	}
+	if r.Opponent != "" {
+		foe = r.Opponent
+	}
+	result := r.Result
+	if result == "" {
+		result = "unfinished"
+	}
+	return fmt.Sprintf("%s vs %s: %s in %d rounds", player, foe, result, r.rounds())
+}
+
+func battleRecordMarkdown(record battleRecord) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Battle %d\n\n", record.ID)
+	fmt.Fprintf(&b, "- Trainer: %s\n", record.Trainer)
+	if record.Opponent != "" {
+		fmt.Fprintf(&b, "- Opponent: %s\n", record.Opponent)
+	}
+	fmt.Fprintf(&b, "- Started: %s\n", record.StartedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "- Seed: %d\n", record.Seed)
+	fmt.Fprintf(&b, "- Result: %s\n\n", record.Result)
+
+	fmt.Fprintln(&b, "## Participants")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "| Side | Pokemon | Level | Types |")
+	fmt.Fprintln(&b, "| --- | --- | --- | --- |")
+	for _, participant := range record.Participants {
+		poke := participant.Pokemon
+		fmt.Fprintf(&b, "| %s | %s | %d | %s |\n", participant.Side, poke.Name, poke.Level, strings.Join(poke.Types, ", "))
+	}
+	fmt.Fprintln(&b)
+
+	fmt.Fprintln(&b, "## Log")
+	round := -1
+	for _, event := range record.Events {
+		if event.Round != round {
+			round = event.Round
+			fmt.Fprintln(&b)
+			if round == 0 {
+				fmt.Fprintln(&b, "### Start")
+			} else {
+				fmt.Fprintf(&b, "### Round %d\n", round)
+			}
+			fmt.Fprintln(&b)
+			for _, action := range record.Actions {
+				if action.Round != round {
+					continue
+				}
+				label := action.Action
+				if action.Detail != "" {
+					label = fmt.Sprintf("%s (%s)", label, action.Detail)
+				}
+				fmt.Fprintf(&b, "- _%s chose %s_\n", action.Side, label)
+			}
+		}
+		fmt.Fprintf(&b, "- %s\n", event.Message)
+	}
+	return b.String()
+}
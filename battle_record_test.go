@@ -0,0 +1,118 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBattleLogPath(t *testing.T) {
+	got := battleLogPath(filepath.Join("data", "ash.json"))
+	want := filepath.Join("data", "ash-battles.jsonl")
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if battleLogPath("") != "" {
+		t.Fatalf("expected empty path for empty storage path")
+	}
+}
+
+func TestAppendBattleRecordAssignsIDs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ash-battles.jsonl")
+	first := battleRecord{Seed: 1, Result: resultWin}
+	second := battleRecord{Seed: 2, Result: resultLoss}
+	if err := appendBattleRecord(path, &first); err != nil {
+		t.Fatalf("append first: %v", err)
+	}
+	if err := appendBattleRecord(path, &second); err != nil {
+		t.Fatalf("append second: %v", err)
+	}
+
+	records, err := loadBattleRecords(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+	if records[0].ID != 1 || records[1].ID != 2 {
+		t.Fatalf("expected ids 1 and 2, got %d and %d", records[0].ID, records[1].ID)
+	}
+	if records[1].Seed != 2 || records[1].Result != resultLoss {
+		t.Fatalf("unexpected second record: %+v", records[1])
+	}
+}
+
+func TestBattleRecordMarkdown(t *testing.T) {
+	record := battleRecord{
+		ID:     3,
+		Result: resultWin,
+		Participants: []battleParticipant{
+			{Side: sidePlayer, Pokemon: pokemonRecord{Name: "pikachu", Level: 12}},
+			{Side: sideWild, Pokemon: pokemonRecord{Name: "pidgey", Level: 4}},
+		},
+		Actions: []battleAction{{Round: 1, Side: sidePlayer, Action: actionFight, Detail: "thunder-shock"}},
+		Events: []battleEvent{
+			{Round: 0, Kind: eventIntro, Message: "A wild pidgey appeared!"},
+			{Round: 1, Kind: eventAttack, Message: "pikachu used thunder-shock for 20 damage!"},
+		},
+	}
+	md := battleRecordMarkdown(record)
+	for _, want := range []string{"# Battle 3", "| player | pikachu | 12 |", "### Round 1", "_player chose fight (thunder-shock)_", "- pikachu used thunder-shock for 20 damage!"} {
+		if !strings.Contains(md, want) {
+			t.Fatalf("expected markdown to contain %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestMirroredRecordSwapsTrainers(t *testing.T) {
+	record := battleRecord{
+		ID:       4,
+		Trainer:  "ash",
+		Opponent: "gary",
+		Result:   resultWin,
+		Participants: []battleParticipant{
+			{Side: sidePlayer, Pokemon: pokemonRecord{Name: "pikachu"}},
+			{Side: sideTrainer, Pokemon: pokemonRecord{Name: "eevee"}},
+		},
+		Actions: []battleAction{{Round: 1, Side: sideTrainer, Action: actionFight, Detail: "tackle"}},
+		Events:  []battleEvent{{Round: 1, Kind: eventAttack, Side: sideTrainer, Target: sidePlayer, Message: "eevee used tackle!"}},
+	}
+	mirror := record.mirrored()
+	if mirror.Trainer != "gary" || mirror.Opponent != "ash" || mirror.Result != resultLoss {
+		t.Fatalf("expected gary's loss to ash, got %+v", mirror)
+	}
+	if mirror.Participants[0].Side != sideTrainer || mirror.Participants[1].Side != sidePlayer {
+		t.Fatalf("expected the participants' sides to swap, got %+v", mirror.Participants)
+	}
+	if mirror.Actions[0].Side != sidePlayer || mirror.Events[0].Side != sidePlayer || mirror.Events[0].Target != sideTrainer {
+		t.Fatalf("expected actions and events to swap sides, got %+v %+v", mirror.Actions[0], mirror.Events[0])
+	}
+	if record.Participants[0].Side != sidePlayer || record.Events[0].Side != sideTrainer {
+		t.Fatalf("expected the original record to be left alone")
+	}
+
+	draw := battleRecord{Result: resultDraw}
+	if draw.mirrored().Result != resultDraw {
+		t.Fatalf("expected a draw to stay a draw")
+	}
+}
+
+func TestReplayStartsFromRecordedHPAndHealsInPlace(t *testing.T) {
+	pidgey := Pokemon{name: "pidgey", level: 10, stats: map[string]int{"hp": 40}, baseStats: map[string]int{"hp": 40}, currentHP: 25}
+	rattata := Pokemon{name: "rattata", level: 10, stats: map[string]int{"hp": 30}, baseStats: map[string]int{"hp": 30}, fainted: true}
+	record := battleRecord{Participants: []battleParticipant{
+		{Side: sidePlayer, Pokemon: pokemonToRecord(pidgey)},
+		{Side: sidePlayer, Pokemon: pokemonToRecord(rattata)},
+	}}
+	state := newReplayState(record)
+	if hp := state.hp[replayKey(sidePlayer, "pidgey")]; hp != 25 {
+		t.Fatalf("expected pidgey to start at its recorded 25 HP, got %d", hp)
+	}
+
+	state.apply(battleEvent{Kind: eventSwitch, Side: sidePlayer, Pokemon: "pidgey", HP: 25})
+	state.apply(battleEvent{Kind: eventHeal, Side: sidePlayer, Pokemon: "rattata", HP: 15})
+	if state.active[sidePlayer] != "pidgey" || state.hp[replayKey(sidePlayer, "rattata")] != 15 {
+		t.Fatalf("expected the revive to heal rattata on the bench, got active %q and %v", state.active[sidePlayer], state.hp)
+	}
+}
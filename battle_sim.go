@@ -0,0 +1,78 @@
+package main
+
+import "math/rand"
+
+const maxSimulatedRounds = 200
+
+// simulateBattle plays two AI-controlled teams against each other without
+// any output. It returns the index of the winning side, or -1 for a draw
+// when both teams fall together or the round limit is reached. observe, when
+// not nil, sees every attack event with the index of the attacking side.
+func simulateBattle(r *rand.Rand, ais [2]BattleAI, teams [2][]Pokemon, observe func(side int, event battleEvent)) (winner int, rounds int) {
+	sides := [2]battleSide{
+		newBattleSide("side-1", "", teams[0]),
+		newBattleSide("side-2", "", teams[1]),
+	}
+	for i := range sides {
+		index := ais[i].chooseReplacement(&sides[i], nil)
+		if index < 0 {
+			return 1 - i, 0
+		}
+		sides[i].bringIn(index)
+	}
+
+	for round := 1; round <= maxSimulatedRounds; round++ {
+		turns := [2]battleTurn{
+			ais[0].chooseTurn(r, &sides[0], *sides[1].current()),
+			ais[1].chooseTurn(r, &sides[1], *sides[0].current()),
+		}
+		for i := range sides {
+			if turns[i].switchTo >= 0 {
+				sides[i].bringIn(turns[i].switchTo)
+			}
+		}
+
+		order := []int{0, 1}
+		switch {
+		case turns[0].switchTo >= 0 && turns[1].switchTo >= 0:
+			order = nil
+		case turns[0].switchTo >= 0:
+			order = []int{1}
+		case turns[1].switchTo >= 0:
+			order = []int{0}
+		case !decideFirst(r, turns[0].move, turns[1].move, *sides[0].current(), *sides[1].current()):
+			order = []int{1, 0}
+		}
+		for _, i := range order {
+			attacker, defender := sides[i].current(), sides[1-i].current()
+			if attacker.current <= 0 || defender.current <= 0 {
+				continue
+			}
+			events := resolveAttack(r, attacker, defender, turns[i].move)
+			if observe != nil {
+				for _, event := range events {
+					observe(i, event)
+				}
+			}
+		}
+		for i := range sides {
+			endOfTurn(r, sides[i].current())
+		}
+
+		left := [2]int{sides[0].remaining(), sides[1].remaining()}
+		switch {
+		case left[0] == 0 && left[1] == 0:
+			return -1, round
+		case left[0] == 0:
+			return 1, round
+		case left[1] == 0:
+			return 0, round
+		}
+		for i := range sides {
+			if sides[i].current().current <= 0 {
+				sides[i].bringIn(ais[i].chooseReplacement(&sides[i], sides[1-i].current()))
+			}
+		}
+	}
+	return -1, maxSimulatedRounds
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+//go:embed data/campaign.json
+var campaignJSON []byte
+
+type campaign struct {
+	Region    string        `json:"region"`
+	LevelCaps []int         `json:"level_caps"`
+	Gyms      []gym         `json:"gyms"`
+	League    pokemonLeague `json:"league"`
+	// FieldMoves names the badge needed to use each field move outside of
+	// battle.
+	FieldMoves map[string]string `json:"field_moves"`
+}
+
+// gym ties a leader to the PokeAPI location (in the campaign's region) where
+// the gym is found.
+type gym struct {
+	Badge    string     `json:"badge"`
+	Location string     `json:"location"`
+	Leader   npcTrainer `json:"leader"`
+	Rewards  []string   `json:"rewards,omitempty"`
+}
+
+type pokemonLeague struct {
+	Location  string       `json:"location"`
+	EliteFour []npcTrainer `json:"elite_four"`
+	Champion  npcTrainer   `json:"champion"`
+}
+
+type hallOfFameEntry struct {
+	InductedAt time.Time       `json:"inducted_at"`
+	Team       []pokemonRecord `json:"team"`
+}
+
+// loadCampaign loads the gym challenge of the region the player's game
+// version is set in.
+func loadCampaign(c *config) (campaign, error) {
+	region := versionRegion(c)
+	data, found, err := campaignFor(region)
+	if err != nil {
+		return campaign{}, err
+	}
+	if !found {
+		return campaign{}, fmt.Errorf("There's no gym challenge in %s yet", itemDisplayName(region))
+	}
+	return data, nil
+}
+
+// campaignFor is the gym challenge of region; found is false for regions
+// without one. Only Kanto has one so far.
+func campaignFor(region string) (data campaign, found bool, err error) {
+	data, err = parseCampaign(campaignJSON)
+	if err != nil || data.Region != region {
+		return campaign{}, false, err
+	}
+	return data, true, nil
+}
+
+func parseCampaign(data []byte) (campaign, error) {
+	var result campaign
+	if err := json.Unmarshal(data, &result); err != nil {
+		return campaign{}, err
+	}
+	if len(result.LevelCaps) != len(result.Gyms)+1 {
+		return campaign{}, errors.New("campaign needs one level cap per badge count")
+	}
+	for _, g := range result.Gyms {
+		if g.Badge == "" || g.Location == "" {
+			return campaign{}, fmt.Errorf("gym led by %s needs a badge and a location", g.Leader.Name)
+		}
+		if err := validateTrainer(g.Leader); err != nil {
+			return campaign{}, err
+		}
+	}
+	for move, badge := range result.FieldMoves {
+		if !hasGymBadge(result.Gyms, badge) {
+			return campaign{}, fmt.Errorf("field move %s needs a badge from the campaign, not %s", move, badge)
+		}
+	}
+	if len(result.League.EliteFour) == 0 {
+		return campaign{}, errors.New("campaign needs an Elite Four")
+	}
+	for _, trainer := range append(result.League.EliteFour, result.League.Champion) {
+		if err := validateTrainer(trainer); err != nil {
+			return campaign{}, err
+		}
+	}
+	return result, nil
+}
+
+func findGym(gyms []gym, name string) (gym, bool) {
+	for _, g := range gyms {
+		if g.Location == name || g.Badge == name || g.Leader.Name == name {
+			return g, true
+		}
+	}
+	return gym{}, false
+}
+
+func hasGymBadge(gyms []gym, badge string) bool {
+	for _, g := range gyms {
+		if g.Badge == badge {
+			return true
+		}
+	}
+	return false
+}
+
+func badgeCount(c *config, gyms []gym) int {
+	count := 0
+	for _, g := range gyms {
+		if _, earned := c.Badges[g.Badge]; earned {
+			count++
+		}
+	}
+	return count
+}
+
+// levelCap is the highest level Pokemon can reach through experience with
+// the player's current badges. Champions have no cap.
+func levelCap(c *config) int {
+	if c == nil || len(c.HallOfFame) > 0 {
+		return maxLevel
+	}
+	data, err := loadCampaign(c)
+	if err != nil {
+		return maxLevel
+	}
+	return data.LevelCaps[badgeCount(c, data.Gyms)]
+}
+
+func awardBadge(c *config, badge string, at time.Time) {
+	if c.Badges == nil {
+		c.Badges = make(map[string]time.Time)
+	}
+	if _, earned := c.Badges[badge]; !earned {
+		c.Badges[badge] = at
+	}
+}
+
+// grantGymRewards gives the items a gym hands out with its badge, skipping
+// any the player already has.
+func grantGymRewards(c *config, g gym) []string {
+	if c.Bag == nil {
+		c.Bag = make(Bag)
+	}
+	granted := make([]string, 0, len(g.Rewards))
+	for _, item := range g.Rewards {
+		if c.Bag[item] > 0 {
+			continue
+		}
+		c.Bag[item] = 1
+		granted = append(granted, item)
+	}
+	return granted
+}
+
+func induct(c *config, team []Pokemon, at time.Time) hallOfFameEntry {
+	entry := hallOfFameEntry{InductedAt: at}
+	for _, pokemon := range team {
+		entry.Team = append(entry.Team, pokemonToRecord(pokemon))
+	}
+	c.HallOfFame = append(c.HallOfFame, entry)
+	return entry
+}
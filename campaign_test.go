@@ -0,0 +1,85 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEmbeddedCampaignParses(t *testing.T) {
+	data, err := loadCampaign(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(data.Gyms) != 8 || len(data.League.EliteFour) != 4 {
+		t.Fatalf("expected 8 gyms and 4 Elite Four members, got %d and %d", len(data.Gyms), len(data.League.EliteFour))
+	}
+	if _, exists := findGym(data.Gyms, "pewter-city"); !exists {
+		t.Fatalf("expected a gym in pewter-city")
+	}
+}
+
+func TestLevelCapFollowsBadges(t *testing.T) {
+	data, err := loadCampaign(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c := &config{}
+	if got := levelCap(c); got != data.LevelCaps[0] {
+		t.Fatalf("expected cap %d without badges, got %d", data.LevelCaps[0], got)
+	}
+	awardBadge(c, data.Gyms[0].Badge, time.Now())
+	awardBadge(c, data.Gyms[1].Badge, time.Now())
+	if got := levelCap(c); got != data.LevelCaps[2] {
+		t.Fatalf("expected cap %d with two badges, got %d", data.LevelCaps[2], got)
+	}
+	induct(c, []Pokemon{{name: "pikachu", level: 60}}, time.Now())
+	if got := levelCap(c); got != maxLevel {
+		t.Fatalf("expected no cap after entering the Hall of Fame, got %d", got)
+	}
+}
+
+func TestGymRewardsAndFieldMoveBadges(t *testing.T) {
+	data, err := loadCampaign(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cascade, _ := findGym(data.Gyms, "cascade-badge")
+	c := &config{Bag: Bag{}}
+	if granted := grantGymRewards(c, cascade); len(granted) != 1 || c.Bag["old-rod"] != 1 {
+		t.Fatalf("expected the old rod from Misty, got %v", granted)
+	}
+	if granted := grantGymRewards(c, cascade); len(granted) != 0 || c.Bag["old-rod"] != 1 {
+		t.Fatalf("expected no second old rod, got %v", granted)
+	}
+
+	if err := commandSurf(c); err == nil {
+		t.Fatalf("expected surf to need a badge")
+	}
+	if err := commandFish(c, "super"); err == nil {
+		t.Fatalf("expected fishing to need the super rod")
+	}
+	awardBadge(c, data.FieldMoves["surf"], time.Now())
+	if err := commandSurf(c); err == nil || err.Error() != "Explore or walk through an area first" {
+		t.Fatalf("expected surf to need an area once the badge is earned, got %v", err)
+	}
+
+	if _, err := parseCampaign([]byte(`{"level_caps":[10],"field_moves":{"surf":"made-up-badge"},"league":{"elite_four":[{"name":"x"}]}}`)); err == nil {
+		t.Fatalf("expected an unknown field move badge to be rejected")
+	}
+}
+
+func TestGymNeedsTheRightRegionAndLocation(t *testing.T) {
+	c := &config{Version: "gold"}
+	if err := commandGym(c, "pewter-city"); err == nil || !strings.Contains(err.Error(), "no gym challenge in Johto") {
+		t.Fatalf("expected Johto to have no gyms yet, got %v", err)
+	}
+
+	c = &config{Version: "red", Location: "pallet-town"}
+	if err := commandGym(c, "pewter-city"); err == nil || !strings.Contains(err.Error(), "Pewter City") {
+		t.Fatalf("expected to be sent to Pewter City first, got %v", err)
+	}
+	if _, earned := c.Badges["boulder-badge"]; earned {
+		t.Fatalf("expected no badge without battling")
+	}
+}
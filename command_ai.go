@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+const (
+	harnessLevel      = 50
+	maxHarnessBattles = 10000
+)
+
+type aiMatchupResult struct {
+	first  string
+	second string
+	wins   int
+	losses int
+	draws  int
+}
+
+func commandAI(c *config, name ...string) error {
+	if len(name) == 0 {
+		fmt.Println()
+		fmt.Println("Battle AI strategies:")
+		for _, strategy := range battleAINames {
+			fmt.Printf("-%s\n", strategy)
+		}
+		fmt.Println()
+		return nil
+	}
+	if name[0] != "harness" || len(name) < 3 {
+		return errors.New("Usage: ai [harness <battles> <pokemon> [pokemon...]]")
+	}
+	battles, err := strconv.Atoi(name[1])
+	if err != nil || battles < 1 || battles > maxHarnessBattles {
+		return fmt.Errorf("Enter between 1 and %d battles", maxHarnessBattles)
+	}
+	if len(name)-2 > maxPartySize {
+		return fmt.Errorf("A team holds at most %d Pokemon", maxPartySize)
+	}
+
+	team := make([]Pokemon, 0, len(name)-2)
+	for _, pokemonName := range name[2:] {
+		resp, err := c.pokeapiClient.GetPokemon(pokemonName)
+		if err != nil {
+			return err
+		}
+		pokemon, err := buildPokemonFromResponse(c, resp)
+		if err != nil {
+			return err
+		}
+		setLevel(&pokemon, harnessLevel)
+		if err := resetMoves(c, &pokemon); err != nil {
+			return err
+		}
+		restoreHP(&pokemon)
+		team = append(team, pokemon)
+	}
+
+	seed := time.Now().UnixNano()
+	results := runAIHarness(battleAINames, team, battles, seed)
+	fmt.Println()
+	fmt.Printf("%d battles per matchup, seed %d:\n", battles, seed)
+	totals := make(map[string][2]int)
+	for _, result := range results {
+		fmt.Printf("-%s vs %s: %d-%d (%d draws), %s wins %.1f%%\n", result.first, result.second, result.wins, result.losses, result.draws, result.first, winRate(result.wins, battles))
+		first, second := totals[result.first], totals[result.second]
+		totals[result.first] = [2]int{first[0] + result.wins, first[1] + battles}
+		totals[result.second] = [2]int{second[0] + result.losses, second[1] + battles}
+	}
+	fmt.Println("Overall win rates:")
+	for _, strategy := range battleAINames {
+		total := totals[strategy]
+		fmt.Printf("-%s: %.1f%%\n", strategy, winRate(total[0], total[1]))
+	}
+	fmt.Println()
+	return nil
+}
+
+// runAIHarness pits every pair of strategies against each other over
+// battles seeded battles with the same team on both sides. The strategies
+// swap sides every battle so neither keeps the speed-tie advantage.
+func runAIHarness(names []string, team []Pokemon, battles int, seed int64) []aiMatchupResult {
+	results := make([]aiMatchupResult, 0)
+	for i := range names {
+		for j := i + 1; j < len(names); j++ {
+			first, _ := lookupBattleAI(names[i])
+			second, _ := lookupBattleAI(names[j])
+			result := aiMatchupResult{first: names[i], second: names[j]}
+			for k := 0; k < battles; k++ {
+				r := rand.New(rand.NewSource(seed + int64(k)))
+				ais, firstSide := [2]BattleAI{first, second}, 0
+				if k%2 == 1 {
+					ais, firstSide = [2]BattleAI{second, first}, 1
+				}
+				winner, _ := simulateBattle(r, ais, [2][]Pokemon{team, team}, nil)
+				switch winner {
+				case -1:
+					result.draws++
+				case firstSide:
+					result.wins++
+				default:
+					result.losses++
+				}
+			}
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+func winRate(wins, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(wins) * 100 / float64(total)
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+func commandBag(c *config, name ...string) error {
+	if len(name) == 0 {
+		printBag(c)
+		return nil
+	}
+	if name[0] == "take" && len(name) >= 2 && len(name) <= 3 {
+		return takeHeldItem(c, name[1:]...)
+	}
+	if name[0] != "use" || len(name) < 3 || len(name) > 4 {
+		return errors.New("Usage: bag [use <item> <pokemon> [number] | take <pokemon> [number]]")
+	}
+	item := lookupItem(name[1])
+	if c.Bag.count(item.name) <= 0 {
+		return fmt.Errorf("You don't have any %s", itemDisplayName(item.name))
+	}
+	selection, err := selectOwnedPokemon(c, name[2:]...)
+	if err != nil {
+		return err
+	}
+	pokemon := c.Pokedex[selection.key][selection.index]
+
+	message, err := useItemOnPokemon(c, item, &pokemon)
+	if err != nil {
+		if errors.Is(err, errNoEffect) {
+			fmt.Println("It won't have any effect.")
+			return nil
+		}
+		return err
+	}
+	c.Bag.take(item.name)
+	syncPlayerPokemon(c, pokemon)
+	if err := saveUserData(c); err != nil {
+		return err
+	}
+	fmt.Println(message)
+	return nil
+}
+
+// takeHeldItem puts the item a Pokemon is holding back in the bag.
+func takeHeldItem(c *config, args ...string) error {
+	selection, err := selectOwnedPokemon(c, args...)
+	if err != nil {
+		return err
+	}
+	pokemon := c.Pokedex[selection.key][selection.index]
+	if pokemon.heldItem == "" {
+		return fmt.Errorf("%s isn't holding anything", pokemon.name)
+	}
+	item := pokemon.heldItem
+	c.Bag.add(item, 1)
+	pokemon.heldItem = ""
+	syncPlayerPokemon(c, pokemon)
+	if err := saveUserData(c); err != nil {
+		return err
+	}
+	fmt.Printf("You took the %s from %s.\n", itemDisplayName(item), pokemon.name)
+	return nil
+}
+
+func printBag(c *config) {
+	fmt.Println()
+	fmt.Printf("Money: $%d\n", c.Money)
+	fmt.Println("Your bag:")
+	items := c.Bag.itemsIn(itemCategoryOrder...)
+	if len(items) == 0 {
+		fmt.Println("-empty")
+	}
+	category := ""
+	for _, name := range items {
+		item := lookupItem(name)
+		if item.category != category {
+			category = item.category
+			fmt.Printf("%s:\n", category)
+		}
+		fmt.Printf("-%s (x%d)\n", itemDisplayName(name), c.Bag.count(name))
+	}
+	fmt.Println()
+}
+
+// useItemOnPokemon applies an item outside of battle. It returns errNoEffect
+// when the item would be wasted so the caller can keep it in the bag.
+func useItemOnPokemon(c *config, item itemDefinition, pokemon *Pokemon) (string, error) {
+	switch item.category {
+	case itemCategoryHealing, itemCategoryStatusCure:
+		if pokemon.fainted {
+			return "", errNoEffect
+		}
+		healed := maxHP(*pokemon) - pokemon.currentHP
+		if !item.fullHeal {
+			healed = min(healed, item.heal)
+		}
+		cured := pokemon.status != "" && item.curesStatus(pokemon.status)
+		if healed <= 0 && !cured {
+			return "", errNoEffect
+		}
+		messages := make([]string, 0, 2)
+		if healed > 0 {
+			pokemon.currentHP += healed
+			messages = append(messages, fmt.Sprintf("%s recovered %d HP!", pokemon.name, healed))
+		}
+		if cured {
+			messages = append(messages, fmt.Sprintf("%s is no longer %s.", pokemon.name, pokemon.status))
+			pokemon.status = ""
+		}
+		return strings.Join(messages, " "), nil
+	case itemCategoryInflict:
+		if pokemon.heldItem == item.name {
+			return "", errNoEffect
+		}
+		message := fmt.Sprintf("%s is now holding the %s.", pokemon.name, itemDisplayName(item.name))
+		if pokemon.heldItem != "" {
+			c.Bag.add(pokemon.heldItem, 1)
+			message = fmt.Sprintf("%s put the %s back in the bag. %s", pokemon.name, itemDisplayName(pokemon.heldItem), message)
+		}
+		pokemon.heldItem = item.name
+		return message, nil
+	case itemCategoryRevival:
+		if !pokemon.fainted {
+			return "", errNoEffect
+		}
+		pokemon.fainted = false
+		pokemon.currentHP = maxHP(*pokemon)
+		if item.reviveHalf {
+			pokemon.currentHP = max(1, pokemon.currentHP/2)
+		}
+		return fmt.Sprintf("%s was revived!", pokemon.name), nil
+	case itemCategoryEvolution:
+		if pokemon.evolutionChain == "" {
+			return "", errNoEffect
+		}
+		chain, err := c.pokeapiClient.GetEvolutionChain(pokemon.evolutionChain)
+		if err != nil {
+			return "", err
+		}
+		options := eligibleEvolutions(chain.Chain, *pokemon, itemEvolutionContext(c, item.name))
+		if len(options) == 0 {
+			return "", errNoEffect
+		}
+		choice, err := chooseEvolution(c, *pokemon, options)
+		if err != nil {
+			return "", err
+		}
+		if choice < 0 {
+			return "", errNoEffect
+		}
+		previous := pokemon.name
+		if err := evolveInto(c, pokemon, options[choice].species); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s evolved into %s!", previous, pokemon.name), nil
+	}
+	return "", fmt.Errorf("%s can't be used here", itemDisplayName(item.name))
+}
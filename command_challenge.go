@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+func commandChallenge(c *config, name ...string) error {
+	trainers, err := loadTrainers()
+	if err != nil {
+		return err
+	}
+	if len(name) == 0 {
+		printTrainers(c, trainers)
+		return nil
+	}
+	if len(name) > 1 {
+		return errors.New("Command challenge takes a single trainer")
+	}
+	trainer, exists := findTrainer(trainers, name[0])
+	if !exists {
+		return fmt.Errorf("No trainer named %s, enter challenge to list trainers", name[0])
+	}
+	_, err = runTrainerBattle(c, trainer)
+	return err
+}
+
+// runTrainerBattle plays a full battle against trainer and returns the
+// recorded result.
+func runTrainerBattle(c *config, trainer npcTrainer) (string, error) {
+	team, err := battleReadyParty(c)
+	if err != nil {
+		return "", err
+	}
+
+	seed := time.Now().UnixNano()
+	rec := newBattleRecorder(c, seed)
+	rec.record.Opponent = trainer.DisplayName
+	defer func() {
+		if err := rec.finish(c); err != nil {
+			fmt.Printf("Warning: failed to record battle: %v\n", err)
+		}
+	}()
+
+	fmt.Println()
+	rec.say(eventIntro, "%s wants to battle!", trainer.DisplayName)
+	foes, err := buildTrainerTeam(c, trainer)
+	if err != nil {
+		return "", err
+	}
+
+	b := newBattleSession(c, rec, seed, team)
+	b.trainer = &trainer
+	b.ai = trainerAI(trainer)
+	b.foe = newBattleSide(sideTrainer, trainer.DisplayName+"'s ", foes)
+	b.sendOutFoe(0)
+	if err := b.sendOut(false); err != nil {
+		if errors.Is(err, errSelectionCancelled) {
+			rec.result = resultCancelled
+			fmt.Println("Battle cancelled")
+			return rec.result, nil
+		}
+		return "", err
+	}
+	err = b.run()
+	return rec.result, err
+}
+
+func printTrainers(c *config, trainers []npcTrainer) {
+	fmt.Println()
+	fmt.Println("Trainers:")
+	for _, trainer := range trainers {
+		status := ""
+		if _, defeated := c.DefeatedTrainers[trainer.Name]; defeated {
+			status = " (defeated)"
+		}
+		fmt.Printf("-%s: %s, %d Pokemon, $%d, %s%s\n", trainer.Name, trainer.DisplayName, len(trainer.Team), trainer.Prize, trainer.Difficulty, status)
+	}
+	fmt.Println()
+}
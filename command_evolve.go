@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// commandEvolve evolves a Pokemon that meets the conditions for one of its
+// evolutions, including with an item or linking cord from the bag.
+func commandEvolve(c *config, args ...string) error {
+	if len(args) == 0 {
+		return errors.New("Usage: evolve <pokemon> [number]")
+	}
+	selection, err := selectOwnedPokemon(c, args...)
+	if err != nil {
+		return err
+	}
+	pokemon := c.Pokedex[selection.key][selection.index]
+	if pokemon.evolutionChain == "" || pokemon.species == "" {
+		return fmt.Errorf("%s can't evolve", pokemon.name)
+	}
+	chain, err := c.pokeapiClient.GetEvolutionChain(pokemon.evolutionChain)
+	if err != nil {
+		return err
+	}
+	if len(findEvolutions(chain.Chain, pokemon.species)) == 0 {
+		return fmt.Errorf("%s doesn't evolve", pokemon.name)
+	}
+
+	ctx := evolutionContext{trigger: evolutionTriggerLevelUp, location: currentLocation(c), now: time.Now()}
+	options := eligibleEvolutions(chain.Chain, pokemon, ctx)
+	for _, item := range c.Bag.itemsIn(itemCategoryEvolution) {
+		options = append(options, eligibleEvolutions(chain.Chain, pokemon, itemEvolutionContext(c, item))...)
+	}
+	if len(options) == 0 {
+		fmt.Printf("%s isn't ready to evolve yet. It evolves by:\n", pokemon.name)
+		for _, evolve := range findEvolutions(chain.Chain, pokemon.species) {
+			for _, detail := range evolve.EvolutionDetails {
+				fmt.Printf("-%s: %s\n", evolve.Species.Name, evolutionOption{species: evolve.Species.Name, detail: detail}.describe())
+			}
+		}
+		return nil
+	}
+
+	choice, err := chooseEvolution(c, pokemon, options)
+	if err != nil {
+		return err
+	}
+	if choice < 0 {
+		fmt.Printf("%s stopped evolving.\n", pokemon.name)
+		return nil
+	}
+	option := options[choice]
+	previous := pokemon.name
+	if err := evolveInto(c, &pokemon, option.species); err != nil {
+		return err
+	}
+	if option.item != "" {
+		c.Bag.take(option.item)
+	}
+	syncPlayerPokemon(c, pokemon)
+	if err := saveUserData(c); err != nil {
+		return err
+	}
+	fmt.Printf("%s evolved into %s!\n", previous, pokemon.name)
+	return nil
+}
@@ -7,34 +7,48 @@ import (
 	"os"
 	"strconv"
 	"strings"
+
+	"github.com/dey12956/pokedexcli/internal/pokeapi"
 )
 
 func commandExplore(c *config, name ...string) error {
 	if len(name) == 0 {
-		return errors.New("Enter an area to explore")
+		return browseLocation(c, currentLocation(c))
 	}
-	if len(name) > 1 {
-		return errors.New("Command explore takes a single area")
+	showRates := len(name) == 2 && name[1] == "rates"
+	if len(name) > 2 || (len(name) == 2 && !showRates) {
+		return errors.New("Command explore takes a single area, optionally followed by rates")
 	}
 
 	fmt.Println()
 	fmt.Printf("Exploring %s...\n", name[0])
 	fmt.Println("Found Pokemon:")
 
-	pokemonResp, err := c.pokeapiClient.ListPokemon(name[0])
+	pokemonResp, err := visitArea(c, name[0])
 	if err != nil {
 		return err
 	}
+	version := currentVersion(c)
+	chances := encounterChances(encounterSlots(pokemonResp, version))
 
-	if len(pokemonResp.PokemonEncounters) == 0 {
+	pokemonNames := versionSpecies(pokemonResp, version)
+	if len(pokemonNames) == 0 {
+		if versions := areaVersions(pokemonResp); len(versions) > 0 {
+			fmt.Printf("None in %s, this area has Pokemon in: %s\n", versionDisplayName(version), strings.Join(versions, ", "))
+		}
 		fmt.Println()
 		return nil
 	}
 
-	pokemonNames := make([]string, 0, len(pokemonResp.PokemonEncounters))
-	for i, pokemonEncounter := range pokemonResp.PokemonEncounters {
-		pokemonNames = append(pokemonNames, pokemonEncounter.Pokemon.Name)
-		fmt.Printf("%d) %s\n", i+1, pokemonEncounter.Pokemon.Name)
+	for i, name := range pokemonNames {
+		line := name
+		if levels, ok := c.encounters[name]; ok {
+			line += " " + levels.String()
+		}
+		if showRates && len(chances[name]) > 0 {
+			line += " - " + formatChances(chances[name])
+		}
+		fmt.Printf("%d) %s\n", i+1, line)
 	}
 	fmt.Println("Hint: enter a number to battle a Pokemon from this area.")
 
@@ -59,6 +73,39 @@ func commandExplore(c *config, name ...string) error {
 			continue
 		}
 		fmt.Println()
-		return commandBattle(c, pokemonNames[choice-1])
+		return battleWild(c, pokemonNames[choice-1], c.encounters[pokemonNames[choice-1]])
+	}
+}
+
+// areaEncounters returns the wild level ranges of the current area, loading
+// them if the area was picked in an earlier session.
+func areaEncounters(c *config) (map[string]levelRange, error) {
+	if c.Area == "" {
+		return nil, errors.New("Explore an area of your location first")
+	}
+	if c.encounters == nil {
+		resp, err := c.pokeapiClient.ListPokemon(c.Area)
+		if err != nil {
+			return nil, err
+		}
+		c.encounters = encounterLevels(resp, currentVersion(c))
+	}
+	return c.encounters, nil
+}
+
+// visitArea fetches an area of the current location and remembers it as the
+// last place the player went, which also counts as a walk with the party.
+func visitArea(c *config, area string) (pokeapi.PokemonResponse, error) {
+	resp, err := c.pokeapiClient.ListPokemon(area)
+	if err != nil {
+		return pokeapi.PokemonResponse{}, err
+	}
+	if here := currentLocation(c); resp.Location.Name != here {
+		return pokeapi.PokemonResponse{}, fmt.Errorf("%s is in %s but you're in %s, enter route to %s for the way there", area, resp.Location.Name, here, resp.Location.Name)
 	}
+	c.Area = area
+	c.encounters = encounterLevels(resp, currentVersion(c))
+	walkParty(c)
+	saveUserData(c)
+	return resp, nil
 }
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/dey12956/pokedexcli/internal/pokeapi"
+)
+
+type fishingRod struct {
+	name   string
+	item   string
+	method string
+}
+
+// fishingRods maps each rod to the encounter method it fishes with, best
+// rod last.
+var fishingRods = []fishingRod{
+	{name: "old", item: "old-rod", method: methodOldRod},
+	{name: "good", item: "good-rod", method: methodGoodRod},
+	{name: "super", item: "super-rod", method: methodSuperRod},
+}
+
+func commandFish(c *config, name ...string) error {
+	if len(name) > 1 {
+		return errors.New("Command fish takes a single rod: old, good or super")
+	}
+	rod := -1
+	if len(name) == 1 {
+		rod = slices.IndexFunc(fishingRods, func(r fishingRod) bool { return r.name == name[0] })
+		if rod < 0 {
+			return errors.New("Unknown rod, choose old, good or super")
+		}
+		if c.Bag[fishingRods[rod].item] <= 0 {
+			return fmt.Errorf("You don't have the %s", itemDisplayName(fishingRods[rod].item))
+		}
+	} else {
+		for i, r := range fishingRods {
+			if c.Bag[r.item] > 0 {
+				rod = i
+			}
+		}
+		if rod < 0 {
+			return errors.New("You don't have a fishing rod")
+		}
+	}
+
+	resp, err := currentArea(c)
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+	fmt.Printf("You cast the %s into the water...\n", itemDisplayName(fishingRods[rod].item))
+	return searchArea(c, resp, encounterSearch{
+		methods: []string{fishingRods[rod].method},
+		tries:   1,
+		empty:   "Nothing bites with this rod here.",
+		miss:    "Not even a nibble...",
+	})
+}
+
+func commandSurf(c *config, name ...string) error {
+	if len(name) != 0 {
+		return errors.New("Command surf doesn't take arguments")
+	}
+	resp, err := fieldMoveArea(c, "surf")
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+	fmt.Println("You surf across the water...")
+	return searchArea(c, resp, encounterSearch{
+		methods: []string{methodSurf},
+		tries:   walkSteps,
+		empty:   "There's no water here to surf on.",
+		miss:    "Nothing appeared.",
+	})
+}
+
+func commandHeadbutt(c *config, name ...string) error {
+	if len(name) != 0 {
+		return errors.New("Command headbutt doesn't take arguments")
+	}
+	resp, err := fieldMoveArea(c, "headbutt")
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+	fmt.Println("You headbutt a tree...")
+	return searchArea(c, resp, encounterSearch{
+		methods: headbuttMethods,
+		tries:   1,
+		empty:   "There are no trees worth headbutting here.",
+		miss:    "Nothing fell out of the tree.",
+	})
+}
+
+func commandSmash(c *config, name ...string) error {
+	if len(name) != 0 {
+		return errors.New("Command smash doesn't take arguments")
+	}
+	resp, err := fieldMoveArea(c, "rock-smash")
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+	fmt.Println("You smash a rock...")
+	return searchArea(c, resp, encounterSearch{
+		methods: []string{methodRockSmash},
+		tries:   1,
+		empty:   "There are no rocks to smash here.",
+		miss:    "Nothing was hiding under the rock.",
+	})
+}
+
+// currentArea fetches the area the player last explored or walked through.
+func currentArea(c *config) (pokeapi.PokemonResponse, error) {
+	if c.Area == "" {
+		return pokeapi.PokemonResponse{}, errors.New("Explore or walk through an area first")
+	}
+	return c.pokeapiClient.ListPokemon(c.Area)
+}
+
+// fieldMoveArea checks the player has the badge a field move needs before
+// fetching the current area. Regions without a gym challenge need no badges.
+func fieldMoveArea(c *config, move string) (pokeapi.PokemonResponse, error) {
+	data, _, err := campaignFor(versionRegion(c))
+	if err != nil {
+		return pokeapi.PokemonResponse{}, err
+	}
+	if badge, gated := data.FieldMoves[move]; gated {
+		if _, earned := c.Badges[badge]; !earned {
+			return pokeapi.PokemonResponse{}, fmt.Errorf("You need the %s to use %s outside of battle", itemDisplayName(badge), itemDisplayName(move))
+		}
+	}
+	return currentArea(c)
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+func commandGym(c *config, name ...string) error {
+	data, err := loadCampaign(c)
+	if err != nil {
+		return err
+	}
+	if len(name) == 0 {
+		printGyms(c, data)
+		return nil
+	}
+	if len(name) > 1 {
+		return errors.New("Command gym takes a single gym")
+	}
+	g, exists := findGym(data.Gyms, name[0])
+	if !exists {
+		return fmt.Errorf("No gym at %s, enter gym to list gyms", name[0])
+	}
+	if location := currentLocation(c); location != g.Location {
+		return fmt.Errorf("You need to be in %s to challenge its gym, you're in %s", itemDisplayName(g.Location), itemDisplayName(location))
+	}
+
+	fmt.Printf("\nWelcome to the %s Gym!\n", itemDisplayName(g.Location))
+	result, err := runTrainerBattle(c, g.Leader)
+	if err != nil || result != resultWin {
+		return err
+	}
+	_, earned := c.Badges[g.Badge]
+	if !earned {
+		awardBadge(c, g.Badge, time.Now())
+	}
+	granted := grantGymRewards(c, g)
+	if err := saveUserData(c); err != nil {
+		return err
+	}
+	if earned {
+		fmt.Printf("You already have the %s.\n", itemDisplayName(g.Badge))
+	} else {
+		fmt.Printf("You earned the %s!\n", itemDisplayName(g.Badge))
+		fmt.Printf("Your Pokemon can now grow up to level %d.\n", levelCap(c))
+	}
+	for _, item := range granted {
+		fmt.Printf("%s gave you the %s!\n", g.Leader.DisplayName, itemDisplayName(item))
+	}
+	return nil
+}
+
+func printGyms(c *config, data campaign) {
+	fmt.Println()
+	fmt.Printf("Gyms in %s:\n", itemDisplayName(data.Region))
+	for _, g := range data.Gyms {
+		status := ""
+		if _, earned := c.Badges[g.Badge]; earned {
+			status = " (badge earned)"
+		}
+		if g.Location == currentLocation(c) {
+			status += " (you are here)"
+		}
+		fmt.Printf("-%s: %s, %s%s\n", g.Location, g.Leader.DisplayName, itemDisplayName(g.Badge), status)
+	}
+	fmt.Println()
+}
+
+func commandLeague(c *config, name ...string) error {
+	if len(name) > 0 {
+		return errors.New("Command league doesn't take arguments")
+	}
+	data, err := loadCampaign(c)
+	if err != nil {
+		return err
+	}
+	if badges := badgeCount(c, data.Gyms); badges < len(data.Gyms) {
+		return fmt.Errorf("You need all %d badges to enter the Pokemon League, you have %d", len(data.Gyms), badges)
+	}
+	if _, err := battleReadyParty(c); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Printf("Welcome to the Pokemon League at %s!\n", itemDisplayName(data.League.Location))
+	fmt.Println("There is no healing between battles. Good luck!")
+	opponents := append(append([]npcTrainer(nil), data.League.EliteFour...), data.League.Champion)
+	for _, trainer := range opponents {
+		result, err := runTrainerBattle(c, trainer)
+		if err != nil {
+			return err
+		}
+		if result != resultWin {
+			fmt.Println("Your league challenge is over. Train up and try again!")
+			return nil
+		}
+	}
+
+	entry := induct(c, partyPokemon(c), time.Now())
+	if err := saveUserData(c); err != nil {
+		return err
+	}
+	fmt.Println()
+	fmt.Println("Congratulations! You are the new Pokemon League Champion!")
+	fmt.Printf("Entered into the Hall of Fame: %s\n", hallOfFameTeam(entry))
+	return nil
+}
+
+func commandBadges(c *config, name ...string) error {
+	if len(name) > 0 {
+		return errors.New("Command badges doesn't take arguments")
+	}
+	data, err := loadCampaign(c)
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+	fmt.Printf("Badges: %d/%d\n", badgeCount(c, data.Gyms), len(data.Gyms))
+	for _, g := range data.Gyms {
+		status := "not yet earned"
+		if earnedAt, earned := c.Badges[g.Badge]; earned {
+			status = "earned " + earnedAt.Format("2006-01-02")
+		}
+		fmt.Printf("-%s (%s, %s): %s\n", itemDisplayName(g.Badge), g.Leader.DisplayName, g.Location, status)
+	}
+	fmt.Printf("Level cap: %d\n", levelCap(c))
+	switch {
+	case len(c.HallOfFame) > 0:
+		fmt.Println("Pokemon League: Champion")
+	case badgeCount(c, data.Gyms) == len(data.Gyms):
+		fmt.Println("Pokemon League: open, challenge it with league")
+	default:
+		fmt.Println("Pokemon League: locked")
+	}
+	if len(c.HallOfFame) > 0 {
+		fmt.Println("Hall of Fame:")
+		for i, entry := range c.HallOfFame {
+			fmt.Printf("#%d %s: %s\n", i+1, entry.InductedAt.Format("2006-01-02"), hallOfFameTeam(entry))
+		}
+	}
+	fmt.Println()
+	return nil
+}
+
+func hallOfFameTeam(entry hallOfFameEntry) string {
+	names := make([]string, 0, len(entry.Team))
+	for _, record := range entry.Team {
+		names = append(names, fmt.Sprintf("%s (Lv %d)", record.Name, record.Level))
+	}
+	return strings.Join(names, ", ")
+}
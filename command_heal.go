@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+func commandHeal(c *config, name ...string) error {
+	if len(name) > 0 {
+		if name[0] != "cooldown" || len(name) != 2 {
+			return errors.New("Usage: heal [cooldown <duration|off>]")
+		}
+		return setHealCooldown(c, name[1])
+	}
+	if len(c.Pokedex) == 0 {
+		return errors.New("No Pokemon in your Pokedex")
+	}
+	now := time.Now()
+	if remaining := healCooldownRemaining(c, now); remaining > 0 {
+		return fmt.Errorf("The Pokemon Center is busy, come back in %s", remaining.Round(time.Second))
+	}
+
+	for key, entries := range c.Pokedex {
+		for i := range entries {
+			healPokemon(&entries[i])
+		}
+		c.Pokedex[key] = entries
+	}
+	c.LastHealAt = now
+	if err := saveUserData(c); err != nil {
+		return err
+	}
+	fmt.Println()
+	fmt.Println("Your Pokemon have been restored to full health.")
+	fmt.Println()
+	return nil
+}
+
+func healPokemon(pokemon *Pokemon) {
+	restoreHP(pokemon)
+	restorePP(pokemon)
+	pokemon.status = ""
+}
+
+func healCooldownRemaining(c *config, now time.Time) time.Duration {
+	if c.HealCooldown <= 0 || c.LastHealAt.IsZero() {
+		return 0
+	}
+	return c.LastHealAt.Add(c.HealCooldown).Sub(now)
+}
+
+func setHealCooldown(c *config, value string) error {
+	cooldown := time.Duration(0)
+	if value != "off" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			return errors.New("Enter a duration like 10m or 1h, or off")
+		}
+		cooldown = parsed
+	}
+	c.HealCooldown = cooldown
+	if err := saveUserData(c); err != nil {
+		return err
+	}
+	if cooldown == 0 {
+		fmt.Println("Pokemon Center cooldown disabled.")
+	} else {
+		fmt.Printf("Pokemon Center cooldown set to %s.\n", cooldown)
+	}
+	return nil
+}
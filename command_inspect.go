@@ -17,7 +17,7 @@ func commandInspect(c *config, name ...string) error {
 
 	if entries, exists := c.Pokedex[name[0]]; exists && len(entries) > 0 {
 		poke := entries[len(entries)-1]
-		fmt.Printf("Name: %s\n", poke.name)
+		fmt.Printf("Name: %s%s\n", poke.name, shinyMarker(poke))
 		fmt.Printf("ID: %d\n", poke.id)
 		if poke.species != "" {
 			fmt.Printf("Species: %s\n", poke.species)
@@ -25,6 +25,7 @@ func commandInspect(c *config, name ...string) error {
 		if poke.level > 0 {
 			fmt.Printf("Level: %d\n", poke.level)
 		}
+		fmt.Printf("HP: %s\n", hpLabel(poke))
 		if poke.experience > 0 {
 			fmt.Printf("XP: %d\n", poke.experience)
 		}
@@ -37,7 +38,7 @@ func commandInspect(c *config, name ...string) error {
 		if len(poke.moves) > 0 {
 			fmt.Println("Move details:")
 			for _, move := range poke.moves {
-				fmt.Printf("-%s (power %d, accuracy %d, priority %d, type %s)\n", move.name, move.power, move.accuracy, move.priority, move.moveType)
+				fmt.Printf("-%s (PP %s, power %d, accuracy %d, priority %d, type %s)\n", move.name, formatPP(move), move.power, move.accuracy, move.priority, move.moveType)
 			}
 		}
 		fmt.Println("Abilities:")
@@ -66,13 +67,24 @@ func commandInspect(c *config, name ...string) error {
 				fmt.Printf("-%s\n", form)
 			}
 		}
+		if poke.gender != "" {
+			fmt.Printf("Gender: %s\n", poke.gender)
+		}
+		if form := formLabel(poke); form != "" {
+			fmt.Printf("Form: %s\n", form)
+		}
+		fmt.Printf("Friendship: %d (%s)\n", poke.friendship, friendshipLabel(poke.friendship))
+		if poke.nature != "" {
+			fmt.Printf("Nature: %s%s\n", poke.nature, natureLabel(poke.nature))
+		}
 		fmt.Println("Stats:")
-		fmt.Printf("-hp: %v\n", poke.stats["hp"])
-		fmt.Printf("-attack: %v\n", poke.stats["attack"])
-		fmt.Printf("-defense: %v\n", poke.stats["defense"])
-		fmt.Printf("-special-attack: %v\n", poke.stats["special-attack"])
-		fmt.Printf("-special-defense: %v\n", poke.stats["special-defense"])
-		fmt.Printf("-speed: %v\n", poke.stats["speed"])
+		for _, stat := range statNames {
+			if len(poke.baseStats) == 0 {
+				fmt.Printf("-%s: %v\n", stat, poke.stats[stat])
+				continue
+			}
+			fmt.Printf("-%s: %v (base %d, IV %d, EV %d)\n", stat, poke.stats[stat], poke.baseStats[stat], poke.ivs[stat], poke.evs[stat])
+		}
 		fmt.Println("Types:")
 		for _, poketype := range poke.types {
 			fmt.Printf("-%s\n", poketype)
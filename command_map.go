@@ -12,8 +12,11 @@ import (
 )
 
 func commandMap(c *config, name ...string) error {
-	if len(name) != 0 {
-		return errors.New("Command map doesn't take arguments")
+	if len(name) > 1 {
+		return errors.New("Command map takes a single region")
+	}
+	if len(name) == 1 {
+		return browseRegion(c, name[0])
 	}
 
 	if c.Next == nil && c.mapFetched {
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+func commandMoves(c *config, args ...string) error {
+	usage := errors.New("Usage: moves <pokemon> [number] [swap <slot> <slot>]")
+	if len(args) == 0 {
+		return usage
+	}
+	selectArgs, swapArgs := args, []string(nil)
+	for i, arg := range args {
+		if arg == "swap" {
+			selectArgs, swapArgs = args[:i], args[i+1:]
+			break
+		}
+	}
+	if swapArgs != nil && len(swapArgs) != 2 {
+		return usage
+	}
+	selection, err := selectOwnedPokemon(c, selectArgs...)
+	if err != nil {
+		return err
+	}
+	pokemon := &c.Pokedex[selection.key][selection.index]
+
+	if swapArgs != nil {
+		a, err := moveSlot(*pokemon, swapArgs[0])
+		if err != nil {
+			return err
+		}
+		b, err := moveSlot(*pokemon, swapArgs[1])
+		if err != nil {
+			return err
+		}
+		pokemon.moves[a], pokemon.moves[b] = pokemon.moves[b], pokemon.moves[a]
+		if err := saveUserData(c); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("%s's moves:\n", selection.label)
+	if len(pokemon.moves) == 0 {
+		fmt.Println("-none")
+	}
+	for i, move := range pokemon.moves {
+		fmt.Printf("%d) %s (PP %s, power %d, accuracy %d, type %s)\n", i+1, move.name, formatPP(move), move.power, move.accuracy, move.moveType)
+	}
+	return nil
+}
+
+func moveSlot(pokemon Pokemon, arg string) (int, error) {
+	slot, err := strconv.Atoi(arg)
+	if err != nil || slot < 1 || slot > len(pokemon.moves) {
+		return 0, fmt.Errorf("Enter a move slot between 1 and %d", len(pokemon.moves))
+	}
+	return slot - 1, nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+const defaultNetPort = 7777
+
+func commandHost(c *config, args ...string) error {
+	if len(args) > 1 {
+		return errors.New("Command host takes an optional port")
+	}
+	port := defaultNetPort
+	if len(args) == 1 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 || n > 65535 {
+			return errors.New("Enter a port between 1 and 65535")
+		}
+		port = n
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	team, err := pickPvPTeam(reader, c)
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+	fmt.Printf("Waiting for a trainer to join on port %d...\n", port)
+	if tcp, ok := listener.(*net.TCPListener); ok {
+		tcp.SetDeadline(time.Now().Add(netWaitTimeout))
+	}
+	conn, err := listener.Accept()
+	if err != nil {
+		return fmt.Errorf("No trainer joined: %v", err)
+	}
+	defer conn.Close()
+
+	seed := time.Now().UnixNano()
+	rec := newBattleRecorder(c, seed)
+	controller := terminalController{reader: reader, name: c.UserName}
+	winner, opponent, err := hostBattle(conn, controller, c, team, rec, seed)
+	if err != nil {
+		return err
+	}
+	return finishNetBattle(c, rec, opponent, winner)
+}
+
+func commandJoin(c *config, args ...string) error {
+	if len(args) != 1 {
+		return errors.New("Enter the host to join as host:port")
+	}
+	address := args[0]
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, strconv.Itoa(defaultNetPort))
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	team, err := pickPvPTeam(reader, c)
+	if err != nil {
+		return err
+	}
+	conn, err := net.DialTimeout("tcp", address, netHandshakeTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	rec := newBattleRecorder(c, 0)
+	controller := terminalController{reader: reader, name: c.UserName}
+	winner, opponent, err := joinBattle(conn, controller, c, team, rec)
+	if err != nil {
+		return err
+	}
+	switch winner {
+	case -1:
+		rec.result = resultDraw
+	case 0:
+		rec.result = resultWin
+	default:
+		rec.result = resultLoss
+	}
+	return finishNetBattle(c, rec, opponent, winner)
+}
+
+func finishNetBattle(c *config, rec *battleRecorder, opponent string, winner int) error {
+	recordPvPResult(c, opponent, winner, 0, time.Now())
+	if err := rec.finish(c); err != nil {
+		fmt.Printf("Warning: failed to record battle: %v\n", err)
+	}
+	return saveUserData(c)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+func commandParty(c *config, name ...string) error {
+	if len(name) == 0 {
+		printParty(c)
+		return nil
+	}
+
+	switch name[0] {
+	case "add":
+		if len(name) < 2 || len(name) > 3 {
+			return errors.New("Usage: party add <pokemon> [number]")
+		}
+		selection, err := selectOwnedPokemonBy(c, func(pokemon Pokemon) bool { return !inParty(c, pokemon.uid) }, name[1:]...)
+		if err != nil {
+			return err
+		}
+		pokemon := c.Pokedex[selection.key][selection.index]
+		if inParty(c, pokemon.uid) {
+			return fmt.Errorf("%s is already in your party", pokemon.name)
+		}
+		if !addToParty(c, pokemon) {
+			return errPartyFull
+		}
+		fmt.Printf("%s joined your party.\n", pokemonLabel(pokemon))
+	case "remove":
+		if len(name) != 2 {
+			return errors.New("Usage: party remove <slot>")
+		}
+		slot, err := partyIndex(c, name[1])
+		if err != nil {
+			return err
+		}
+		if len(c.Party) == 1 {
+			return errors.New("Your party needs at least one Pokemon")
+		}
+		removed := c.Party[slot]
+		c.Party = append(c.Party[:slot], c.Party[slot+1:]...)
+		if selection, ok := findOwnedPokemon(c, removed); ok {
+			fmt.Printf("%s was sent to storage.\n", selection.label)
+		}
+	case "swap":
+		if len(name) != 3 {
+			return errors.New("Usage: party swap <slot> <slot>")
+		}
+		a, err := partyIndex(c, name[1])
+		if err != nil {
+			return err
+		}
+		b, err := partyIndex(c, name[2])
+		if err != nil {
+			return err
+		}
+		c.Party[a], c.Party[b] = c.Party[b], c.Party[a]
+	default:
+		return errors.New("Usage: party [add <pokemon> [number] | remove <slot> | swap <slot> <slot>]")
+	}
+
+	if err := saveUserData(c); err != nil {
+		return err
+	}
+	printParty(c)
+	return nil
+}
+
+func printParty(c *config) {
+	fmt.Println()
+	fmt.Println("Your party:")
+	members := partyPokemon(c)
+	if len(members) == 0 {
+		fmt.Println("-empty")
+	}
+	for i, member := range members {
+		fmt.Printf("%d) %s %s\n", i+1, pokemonLabel(member), hpLabel(member))
+	}
+	fmt.Println()
+}
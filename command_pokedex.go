@@ -24,6 +24,16 @@ func commandPokedex(c *config, name ...string) error {
 	}
 	sort.Strings(names)
 	for _, name := range names {
+		shiny := 0
+		for _, pokemon := range c.Pokedex[name] {
+			if pokemon.shiny {
+				shiny++
+			}
+		}
+		if shiny > 0 {
+			fmt.Printf("-%s (x%d, %d shiny)\n", name, len(c.Pokedex[name]), shiny)
+			continue
+		}
 		fmt.Printf("-%s (x%d)\n", name, len(c.Pokedex[name]))
 	}
 	fmt.Println()
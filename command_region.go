@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func commandRegions(c *config, name ...string) error {
+	if len(name) != 0 {
+		return errors.New("Command regions doesn't take arguments")
+	}
+	resp, err := c.pokeapiClient.ListRegions()
+	if err != nil {
+		return err
+	}
+	regions := make([]string, 0, len(resp.Results))
+	for _, region := range resp.Results {
+		regions = append(regions, region.Name)
+	}
+
+	fmt.Println()
+	fmt.Println("Regions:")
+	choice, err := promptListSelection(regions, "Region")
+	if err != nil || choice < 0 {
+		return err
+	}
+	return browseRegion(c, regions[choice])
+}
+
+// browseRegion lists a region's locations and opens the one picked.
+func browseRegion(c *config, region string) error {
+	resp, err := c.pokeapiClient.GetRegion(region)
+	if err != nil {
+		return err
+	}
+	locations := make([]string, 0, len(resp.Locations))
+	for _, location := range resp.Locations {
+		locations = append(locations, location.Name)
+	}
+
+	fmt.Println()
+	fmt.Printf("%s:\n", mapBreadcrumb(resp.Name, ""))
+	choice, err := promptListSelection(locations, "Location")
+	if err != nil || choice < 0 {
+		return err
+	}
+	return browseLocation(c, locations[choice])
+}
+
+// browseLocation lists a location's areas and explores the one picked.
+// Locations with a single area go straight to it.
+func browseLocation(c *config, location string) error {
+	resp, err := c.pokeapiClient.GetLocation(location)
+	if err != nil {
+		return err
+	}
+	areas := make([]string, 0, len(resp.Areas))
+	for _, area := range resp.Areas {
+		areas = append(areas, area.Name)
+	}
+	switch len(areas) {
+	case 0:
+		fmt.Printf("There are no areas to explore in %s.\n", location)
+		return nil
+	case 1:
+		return commandExplore(c, areas[0])
+	}
+
+	region := ""
+	if resp.Region != nil {
+		region = resp.Region.Name
+	}
+	fmt.Println()
+	fmt.Printf("%s:\n", mapBreadcrumb(region, resp.Name))
+	choice, err := promptListSelection(areas, "Area")
+	if err != nil || choice < 0 {
+		return err
+	}
+	return commandExplore(c, areas[choice])
+}
+
+// mapBreadcrumb shows where in the region, location, area hierarchy the
+// player is.
+func mapBreadcrumb(region, location string) string {
+	parts := []string{"Map"}
+	for _, part := range []string{region, location} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, " > ")
+}
+
+// promptListSelection numbers items and reads a choice, returning -1 when
+// the player presses Enter to leave.
+func promptListSelection(items []string, label string) (int, error) {
+	if len(items) == 0 {
+		fmt.Println("-empty")
+		fmt.Println()
+		return -1, nil
+	}
+	for i, item := range items {
+		fmt.Printf("%d) %s\n", i+1, item)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Printf("%s # (or press Enter to continue) > ", label)
+		input, needsNewline, err := readLine(reader)
+		if err != nil {
+			return -1, err
+		}
+		if needsNewline {
+			fmt.Println()
+		}
+		input = strings.TrimSpace(input)
+		if input == "" {
+			fmt.Println()
+			return -1, nil
+		}
+		choice, err := strconv.Atoi(input)
+		if err != nil || choice < 1 || choice > len(items) {
+			fmt.Printf("Enter 1-%d, or press Enter to continue.\n", len(items))
+			continue
+		}
+		return choice - 1, nil
+	}
+}
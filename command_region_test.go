@@ -0,0 +1,23 @@
+package main
+
+import "testing"
+
+func TestMapBreadcrumb(t *testing.T) {
+	tests := map[string]struct {
+		region   string
+		location string
+		want     string
+	}{
+		"top":      {want: "Map"},
+		"region":   {region: "kanto", want: "Map > kanto"},
+		"location": {region: "kanto", location: "viridian-forest", want: "Map > kanto > viridian-forest"},
+		"orphan":   {location: "mystery-zone", want: "Map > mystery-zone"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := mapBreadcrumb(tc.region, tc.location); got != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
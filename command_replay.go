@@ -0,0 +1,170 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const replayRoundDelay = 800 * time.Millisecond
+
+func commandReplays(c *config, name ...string) error {
+	if len(name) != 0 {
+		return errors.New("Command replays doesn't take arguments")
+	}
+	records, err := loadBattleRecords(battleLogPath(c.StoragePath))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println("Recorded battles:")
+	if len(records) == 0 {
+		fmt.Println("-none")
+		fmt.Println()
+		return nil
+	}
+	for _, record := range records {
+		fmt.Printf("%d) %s %s\n", record.ID, record.StartedAt.Format("2006-01-02 15:04"), record.summary())
+	}
+	fmt.Println()
+	return nil
+}
+
+func commandReplay(c *config, name ...string) error {
+	if len(name) == 0 {
+		return errors.New("Enter a battle id to replay")
+	}
+	if len(name) > 2 {
+		return errors.New("Usage: replay <id> [speed|export]")
+	}
+	id, err := strconv.Atoi(name[0])
+	if err != nil {
+		return errors.New("Battle id must be a number")
+	}
+	records, err := loadBattleRecords(battleLogPath(c.StoragePath))
+	if err != nil {
+		return err
+	}
+	record, err := findBattleRecord(records, id)
+	if err != nil {
+		return err
+	}
+
+	speed := 1.0
+	if len(name) == 2 {
+		if name[1] == "export" {
+			return exportBattleRecord(c, record)
+		}
+		speed, err = strconv.ParseFloat(name[1], 64)
+		if err != nil || speed <= 0 {
+			return errors.New("Speed must be a positive number (e.g. 0.5, 2)")
+		}
+	}
+	delay := time.Duration(float64(replayRoundDelay) / speed)
+
+	state := newReplayState(record)
+
+	fmt.Println()
+	fmt.Printf("Replaying battle %d (seed %d)\n", record.ID, record.Seed)
+	round := 0
+	for _, event := range record.Events {
+		if event.Round != round {
+			state.print()
+			time.Sleep(delay)
+			round = event.Round
+			fmt.Printf("\nRound %d\n", round)
+		}
+		state.apply(event)
+		fmt.Println(event.Message)
+	}
+	state.print()
+	fmt.Printf("Result: %s\n", record.Result)
+	fmt.Println()
+	return nil
+}
+
+type replayState struct {
+	active map[string]string
+	hp     map[string]int
+	maxHP  map[string]int
+}
+
+func newReplayState(record battleRecord) *replayState {
+	state := &replayState{
+		active: make(map[string]string),
+		hp:     make(map[string]int),
+		maxHP:  make(map[string]int),
+	}
+	for _, participant := range record.Participants {
+		poke := recordToPokemon(participant.Pokemon)
+		key := replayKey(participant.Side, poke.name)
+		state.maxHP[key] = maxHP(poke)
+		state.hp[key] = min(state.maxHP[key], poke.currentHP)
+		if participant.Side == sideWild {
+			state.active[sideWild] = poke.name
+		}
+	}
+	return state
+}
+
+func replayKey(side, name string) string {
+	return side + ":" + name
+}
+
+func (s *replayState) apply(event battleEvent) {
+	switch event.Kind {
+	case eventAttack, eventMiss:
+		defender := event.Target
+		if defender == "" {
+			defender = opposingSide(event.Side)
+		}
+		s.hp[replayKey(defender, s.active[defender])] = event.HP
+	case eventRecoil, eventSwitch:
+		s.active[event.Side] = event.Pokemon
+		s.hp[replayKey(event.Side, event.Pokemon)] = event.HP
+	case eventHeal, eventStatus:
+		// A revive heals a Pokemon on the bench, so only its HP changes.
+		s.hp[replayKey(event.Side, event.Pokemon)] = event.HP
+	}
+}
+
+func (s *replayState) print() {
+	if name, ok := s.active[sidePlayer]; ok {
+		key := replayKey(sidePlayer, name)
+		fmt.Printf("Your %s HP: %d/%d\n", name, s.hp[key], s.maxHP[key])
+	}
+	if name, ok := s.active[sideWild]; ok {
+		key := replayKey(sideWild, name)
+		fmt.Printf("Wild %s HP: %d/%d\n", name, s.hp[key], s.maxHP[key])
+	}
+	if name, ok := s.active[sideTrainer]; ok {
+		key := replayKey(sideTrainer, name)
+		fmt.Printf("Foe %s HP: %d/%d\n", name, s.hp[key], s.maxHP[key])
+	}
+}
+
+func opposingSide(side string) string {
+	if side == sidePlayer {
+		return sideWild
+	}
+	return sidePlayer
+}
+
+func exportBattleRecord(c *config, record battleRecord) error {
+	logPath := battleLogPath(c.StoragePath)
+	if logPath == "" {
+		return errors.New("No storage path available for export")
+	}
+	base := strings.TrimSuffix(filepath.Base(logPath), ".jsonl")
+	path := filepath.Join(filepath.Dir(logPath), fmt.Sprintf("%s-%d.md", base, record.ID))
+	if err := os.WriteFile(path, []byte(battleRecordMarkdown(record)), 0o600); err != nil {
+		return err
+	}
+	fmt.Printf("Exported battle %d to %s\n", record.ID, path)
+	return nil
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSimulationRuns = 10000
+	maxSimulationRuns     = 1000000
+)
+
+type simulationStats struct {
+	wins   [2]int
+	draws  int
+	rounds int
+	damage [2]damageHistogram
+	misses [2]int
+}
+
+// damageHistogram counts how often each damage value was dealt.
+type damageHistogram map[int]int
+
+func newSimulationStats() *simulationStats {
+	return &simulationStats{damage: [2]damageHistogram{make(damageHistogram), make(damageHistogram)}}
+}
+
+func (s *simulationStats) observe(side int, event battleEvent) {
+	switch event.Kind {
+	case eventAttack:
+		if event.Damage > 0 {
+			s.damage[side][event.Damage]++
+		}
+	case eventMiss:
+		s.misses[side]++
+	}
+}
+
+func (s *simulationStats) record(winner, rounds int) {
+	if winner < 0 {
+		s.draws++
+	} else {
+		s.wins[winner]++
+	}
+	s.rounds += rounds
+}
+
+func (s *simulationStats) merge(other *simulationStats) {
+	for side := range s.wins {
+		s.wins[side] += other.wins[side]
+		s.misses[side] += other.misses[side]
+		for damage, count := range other.damage[side] {
+			s.damage[side][damage] += count
+		}
+	}
+	s.draws += other.draws
+	s.rounds += other.rounds
+}
+
+func (s *simulationStats) runs() int {
+	return s.wins[0] + s.wins[1] + s.draws
+}
+
+func (h damageHistogram) hits() (count, sum int) {
+	for damage, n := range h {
+		count += n
+		sum += damage * n
+	}
+	return count, sum
+}
+
+// percentile returns the smallest damage value with at least p of the hits
+// at or below it.
+func (h damageHistogram) percentile(p float64) int {
+	count, _ := h.hits()
+	if count == 0 {
+		return 0
+	}
+	values := make([]int, 0, len(h))
+	for damage := range h {
+		values = append(values, damage)
+	}
+	sort.Ints(values)
+	target := int(math.Ceil(p * float64(count)))
+	seen := 0
+	for _, damage := range values {
+		seen += h[damage]
+		if seen >= target {
+			return damage
+		}
+	}
+	return values[len(values)-1]
+}
+
+func commandSimulate(c *config, name ...string) error {
+	groups, level, runs, err := parseSimulateArgs(name)
+	if err != nil {
+		return err
+	}
+	var combatants [2]Pokemon
+	var labels [2]string
+	for i, group := range groups {
+		combatants[i], labels[i], err = simulationCombatant(c, group, level)
+		if err != nil {
+			return err
+		}
+	}
+
+	seed := time.Now().UnixNano()
+	stats := runSimulation(combatants, runs, seed, runtime.NumCPU())
+	fmt.Println()
+	fmt.Printf("Simulated %d battles (seed %d): %s vs %s\n", runs, seed, labels[0], labels[1])
+	fmt.Println("Both sides pick their most damaging move each turn.")
+	for i, pokemon := range combatants {
+		fmt.Printf("%s wins: %.1f%%\n", pokemon.name, winRate(stats.wins[i], runs))
+	}
+	fmt.Printf("Draws: %.1f%%\n", winRate(stats.draws, runs))
+	fmt.Printf("Average turns: %.1f\n", float64(stats.rounds)/float64(runs))
+	for i, pokemon := range combatants {
+		hits, sum := stats.damage[i].hits()
+		if hits == 0 {
+			fmt.Printf("%s never dealt damage\n", pokemon.name)
+			continue
+		}
+		fmt.Printf("Damage per hit by %s: avg %.1f, min %d, p10 %d, median %d, p90 %d, max %d (miss rate %.1f%%)\n",
+			pokemon.name,
+			float64(sum)/float64(hits),
+			stats.damage[i].percentile(0),
+			stats.damage[i].percentile(0.1),
+			stats.damage[i].percentile(0.5),
+			stats.damage[i].percentile(0.9),
+			stats.damage[i].percentile(1),
+			winRate(stats.misses[i], hits+stats.misses[i]),
+		)
+	}
+	fmt.Println()
+	return nil
+}
+
+// parseSimulateArgs splits the arguments into two Pokemon, each a name with
+// an optional number picking one of your own, plus the --level and --runs
+// flags.
+func parseSimulateArgs(args []string) ([][]string, int, int, error) {
+	usage := errors.New("Usage: simulate <pokemon-a> [number] <pokemon-b> [number] [--level N] [--runs N]")
+	level, runs := 0, defaultSimulationRuns
+	groups := make([][]string, 0, 2)
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--level", "--runs":
+			if i+1 >= len(args) {
+				return nil, 0, 0, usage
+			}
+			value, err := strconv.Atoi(args[i+1])
+			if args[i] == "--level" {
+				if err != nil || value < 1 || value > maxLevel {
+					return nil, 0, 0, fmt.Errorf("Enter a level between 1 and %d", maxLevel)
+				}
+				level = value
+			} else {
+				if err != nil || value < 1 || value > maxSimulationRuns {
+					return nil, 0, 0, fmt.Errorf("Enter between 1 and %d runs", maxSimulationRuns)
+				}
+				runs = value
+			}
+			i++
+			continue
+		}
+		if _, err := strconv.Atoi(args[i]); err == nil && len(groups) > 0 && len(groups[len(groups)-1]) == 1 {
+			groups[len(groups)-1] = append(groups[len(groups)-1], args[i])
+			continue
+		}
+		groups = append(groups, []string{args[i]})
+	}
+	if len(groups) != 2 {
+		return nil, 0, 0, usage
+	}
+	return groups, level, runs, nil
+}
+
+// simulationCombatant uses your own Pokemon, with its current HP, when the
+// name is in your Pokedex. With a level it fights as a copy raised or
+// lowered to that level at full health, keeping its moves. Anything else is
+// fetched as a wild Pokemon.
+func simulationCombatant(c *config, group []string, level int) (Pokemon, string, error) {
+	if _, owned := c.Pokedex[group[0]]; owned || len(group) > 1 {
+		selection, err := selectOwnedPokemon(c, group...)
+		if err != nil {
+			return Pokemon{}, "", err
+		}
+		pokemon := c.Pokedex[selection.key][selection.index]
+		if pokemon.fainted {
+			return Pokemon{}, "", fmt.Errorf("Your %s has fainted, visit the Pokemon Center with heal", pokemon.name)
+		}
+		if level > 0 {
+			setLevel(&pokemon, level)
+			restoreHP(&pokemon)
+			return pokemon, fmt.Sprintf("your %s at Lv %d %s", selection.label, level, hpLabel(pokemon)), nil
+		}
+		return pokemon, fmt.Sprintf("your %s %s", selection.label, hpLabel(pokemon)), nil
+	}
+
+	resp, err := c.pokeapiClient.GetPokemon(group[0])
+	if err != nil {
+		return Pokemon{}, "", err
+	}
+	pokemon, err := buildPokemonFromResponse(c, resp)
+	if err != nil {
+		return Pokemon{}, "", err
+	}
+	if level > 0 {
+		setLevel(&pokemon, level)
+		if err := resetMoves(c, &pokemon); err != nil {
+			return Pokemon{}, "", err
+		}
+	}
+	restoreHP(&pokemon)
+	return pokemon, pokemonLabel(pokemon), nil
+}
+
+// runSimulation splits runs across workers, each with its own seeded
+// random source, and merges their results. Both sides use the same AI so
+// neither has an edge the matchup doesn't give it.
+func runSimulation(combatants [2]Pokemon, runs int, seed int64, workers int) *simulationStats {
+	workers = max(1, min(workers, runs))
+	results := make([]*simulationStats, workers)
+	var wg sync.WaitGroup
+	for w := range workers {
+		count := runs / workers
+		if w < runs%workers {
+			count++
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(seed + int64(w)))
+			stats := newSimulationStats()
+			ais := [2]BattleAI{greedyAI{}, greedyAI{}}
+			teams := [2][]Pokemon{{combatants[0]}, {combatants[1]}}
+			for range count {
+				stats.record(simulateBattle(r, ais, teams, stats.observe))
+			}
+			results[w] = stats
+		}()
+	}
+	wg.Wait()
+
+	total := newSimulationStats()
+	for _, stats := range results {
+		total.merge(stats)
+	}
+	return total
+}
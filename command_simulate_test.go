@@ -0,0 +1,105 @@
+package main
+
+import "testing"
+
+func TestParseSimulateArgs(t *testing.T) {
+	groups, level, runs, err := parseSimulateArgs([]string{"pikachu", "2", "pidgey", "--level", "12", "--runs", "500"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(groups) != 2 || len(groups[0]) != 2 || groups[1][0] != "pidgey" {
+		t.Fatalf("unexpected groups: %v", groups)
+	}
+	if level != 12 || runs != 500 {
+		t.Fatalf("expected level 12 and 500 runs, got %d and %d", level, runs)
+	}
+	if _, _, _, err := parseSimulateArgs([]string{"pikachu"}); err == nil {
+		t.Fatalf("expected an error for a single Pokemon")
+	}
+}
+
+func TestRunSimulationCountsEveryRun(t *testing.T) {
+	strong := Pokemon{
+		name:      "machamp",
+		level:     50,
+		types:     []string{"fighting"},
+		stats:     map[string]int{"hp": 90, "attack": 130, "defense": 80, "speed": 55},
+		currentHP: 190,
+		moves:     []PokemonMove{{name: "karate-chop", power: 50, accuracy: 100, moveType: "fighting"}},
+	}
+	weak := Pokemon{
+		name:      "rattata",
+		level:     5,
+		types:     []string{"normal"},
+		stats:     map[string]int{"hp": 30, "attack": 56, "defense": 35, "speed": 72},
+		currentHP: 40,
+		moves:     []PokemonMove{{name: "tackle", power: 40, accuracy: 100, moveType: "normal"}},
+	}
+	stats := runSimulation([2]Pokemon{strong, weak}, 101, 1, 4)
+	if stats.runs() != 101 {
+		t.Fatalf("expected 101 runs, got %d", stats.runs())
+	}
+	if stats.wins[0] != 101 {
+		t.Fatalf("expected the stronger Pokemon to win every run, got %+v", stats.wins)
+	}
+	if hits, _ := stats.damage[0].hits(); hits == 0 {
+		t.Fatalf("expected recorded hits")
+	}
+}
+
+func TestDamageHistogramPercentile(t *testing.T) {
+	h := damageHistogram{5: 1, 10: 8, 20: 1}
+	if h.percentile(0) != 5 || h.percentile(0.5) != 10 || h.percentile(1) != 20 {
+		t.Fatalf("unexpected percentiles: %d %d %d", h.percentile(0), h.percentile(0.5), h.percentile(1))
+	}
+}
+
+func TestRunSimulationTreatsSidesAlike(t *testing.T) {
+	pokemon := Pokemon{
+		name:      "rattata",
+		level:     5,
+		types:     []string{"normal"},
+		stats:     map[string]int{"hp": 30, "attack": 56, "defense": 35, "speed": 72},
+		currentHP: 40,
+		moves: []PokemonMove{
+			{name: "growl", accuracy: 100, moveType: "normal", damageClass: "status"},
+			{name: "tackle", power: 40, accuracy: 100, moveType: "normal", damageClass: "physical"},
+		},
+	}
+	stats := runSimulation([2]Pokemon{pokemon, pokemon}, 400, 1, 4)
+	if stats.wins[0] == 0 || stats.wins[1] == 0 {
+		t.Fatalf("expected a mirror match to be won by both sides, got %+v", stats.wins)
+	}
+	if stats.misses[0] != 0 || stats.misses[1] != 0 {
+		t.Fatalf("expected neither side to waste turns, got %+v misses", stats.misses)
+	}
+	hits0, _ := stats.damage[0].hits()
+	hits1, _ := stats.damage[1].hits()
+	if hits0 == 0 || hits1 == 0 {
+		t.Fatalf("expected both sides to attack, got %d and %d hits", hits0, hits1)
+	}
+}
+
+func TestSimulationCombatantAppliesLevelToOwnedPokemon(t *testing.T) {
+	pikachu := Pokemon{
+		name:      "pikachu",
+		level:     12,
+		nature:    "hardy",
+		baseStats: map[string]int{"hp": 35, "attack": 55, "defense": 40, "special-attack": 50, "special-defense": 50, "speed": 90},
+		moves:     []PokemonMove{{name: "thunder-shock", power: 40, accuracy: 100, moveType: "electric"}},
+	}
+	recalculateStats(&pikachu)
+	pikachu.currentHP = 1
+	c := &config{Pokedex: map[string][]Pokemon{"pikachu": {pikachu}}}
+
+	pokemon, _, err := simulationCombatant(c, []string{"pikachu"}, 40)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pokemon.level != 40 || pokemon.currentHP != maxHP(pokemon) || pokemon.moves[0].name != "thunder-shock" {
+		t.Fatalf("expected a full-health Lv 40 copy with its own moves, got Lv %d %d HP %v", pokemon.level, pokemon.currentHP, pokemon.moves)
+	}
+	if c.Pokedex["pikachu"][0].level != 12 || c.Pokedex["pikachu"][0].currentHP != 1 {
+		t.Fatalf("expected the owned Pokemon to be left alone")
+	}
+}
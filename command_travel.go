@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+func commandTravel(c *config, name ...string) error {
+	if len(name) > 1 {
+		return errors.New("Command travel takes a single location")
+	}
+	graph, err := loadRouteGraph()
+	if err != nil {
+		return err
+	}
+	here := currentLocation(c)
+	if len(name) == 0 {
+		fmt.Println()
+		fmt.Printf("You are in %s.\n", here)
+		if next := graph[here]; len(next) > 0 {
+			fmt.Println("From here you can travel to:")
+			for _, location := range next {
+				fmt.Printf("-%s\n", location)
+			}
+		}
+		fmt.Println()
+		return nil
+	}
+
+	if err := graph.checkTravel(here, name[0]); err != nil {
+		return err
+	}
+	resp, err := c.pokeapiClient.GetLocation(name[0])
+	if err != nil {
+		return err
+	}
+	c.Location = resp.Name
+	c.Area = ""
+	if len(resp.Areas) == 1 {
+		c.Area = resp.Areas[0].Name
+	}
+	c.encounters = nil
+	walkParty(c)
+	if err := saveUserData(c); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Printf("You traveled to %s.\n", resp.Name)
+	if len(resp.Areas) > 0 {
+		areas := make([]string, 0, len(resp.Areas))
+		for _, area := range resp.Areas {
+			areas = append(areas, area.Name)
+		}
+		fmt.Printf("Areas to explore: %s\n", strings.Join(areas, ", "))
+	}
+	fmt.Println()
+	return nil
+}
+
+func commandRoute(c *config, name ...string) error {
+	if len(name) != 2 || name[0] != "to" {
+		return errors.New("Enter route to <location>")
+	}
+	graph, err := loadRouteGraph()
+	if err != nil {
+		return err
+	}
+	here := currentLocation(c)
+	path, found := graph.shortestRoute(here, name[1])
+	if !found {
+		return fmt.Errorf("No known route from %s to %s", here, name[1])
+	}
+	if len(path) == 1 {
+		fmt.Printf("You're already in %s.\n", here)
+		return nil
+	}
+	fmt.Println()
+	fmt.Printf("Route to %s (%d stops):\n", name[1], len(path)-1)
+	fmt.Println(strings.Join(path, " -> "))
+	fmt.Println()
+	return nil
+}
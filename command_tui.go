@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,6 +20,7 @@ type tuiState struct {
 	mapTable      *tview.Table
 	pokemonList   *tview.List
 	asciiView     *tview.TextView
+	dexSprite     *tview.TextView
 	statusView    *tview.TextView
 	root          *tview.Flex
 	pokedexList   *tview.List
@@ -27,6 +29,9 @@ type tuiState struct {
 	locations     []string
 	next          *string
 	prev          *string
+	mapLevel      string
+	region        string
+	location      string
 	selected      string
 	selectedPkm   string
 	inspectName   string
@@ -43,8 +48,21 @@ const (
 	viewInspect = "inspect"
 )
 
-const tuiHelpTextFull = "(h/j/k/l) move (tab) switch (m) map (d) dex (i) inspect (n) next (p) prev (q) quit"
-const tuiHelpTextShort = "Window small: resize for full help. (h/j/k/l) move (tab) switch (m) map (d) dex (i) inspect (q) quit"
+// The map pane drills down from regions to their locations and then their
+// areas, or pages through every area at once.
+const (
+	mapLevelRegions   = "regions"
+	mapLevelLocations = "locations"
+	mapLevelAreas     = "areas"
+	mapLevelAll       = "all"
+)
+
+// allAreasRow is the first row of the region list, which opens the paged
+// list of every area.
+const allAreasRow = "(all areas)"
+
+const tuiHelpTextFull = "(h/j/k/l) move (enter) open (b) back (tab) switch (m) map (d) dex (i) inspect (n) next (p) prev (q) quit"
+const tuiHelpTextShort = "Window small: resize for full help. (h/j/k/l) move (enter) open (b) back (tab) switch (m) map (d) dex (i) inspect (q) quit"
 
 func commandTui(c *config, name ...string) error {
 	if len(name) != 0 {
@@ -75,6 +93,9 @@ func commandTui(c *config, name ...string) error {
 	pokedexView := tview.NewTextView().SetDynamicColors(false)
 	pokedexView.SetBorder(true).SetTitle("Details")
 	pokedexView.SetBackgroundColor(tcell.ColorBlack)
+	dexSprite := tview.NewTextView().SetDynamicColors(false)
+	dexSprite.SetBorder(true).SetTitle("Sprite")
+	dexSprite.SetBackgroundColor(tcell.ColorBlack)
 	inspectView := tview.NewTextView().SetDynamicColors(false)
 	inspectView.SetBorder(true).SetTitle("Inspect")
 	inspectView.SetBackgroundColor(tcell.ColorBlack)
@@ -86,6 +107,7 @@ func commandTui(c *config, name ...string) error {
 		mapTable:    mapTable,
 		pokemonList: pokemonList,
 		asciiView:   asciiView,
+		dexSprite:   dexSprite,
 		statusView:  statusView,
 		pokedexList: pokedexList,
 		pokedexView: pokedexView,
@@ -104,9 +126,13 @@ func commandTui(c *config, name ...string) error {
 	main.AddItem(mapTable, 0, 2, true)
 	main.AddItem(right, 0, 3, false)
 
+	dexRight := tview.NewFlex().SetDirection(tview.FlexRow)
+	dexRight.AddItem(pokedexView, 0, 3, false)
+	dexRight.AddItem(dexSprite, 0, 2, false)
+
 	pokedexPage := tview.NewFlex()
 	pokedexPage.AddItem(pokedexList, 0, 2, true)
-	pokedexPage.AddItem(pokedexView, 0, 3, false)
+	pokedexPage.AddItem(dexRight, 0, 3, false)
 
 	inspectPage := tview.NewFlex()
 	inspectPage.AddItem(inspectView, 0, 1, true)
@@ -123,6 +149,9 @@ func commandTui(c *config, name ...string) error {
 	mapTable.SetSelectionChangedFunc(func(row, column int) {
 		state.selectLocation(row, column)
 	})
+	mapTable.SetSelectedFunc(func(row, column int) {
+		state.openLocation(row)
+	})
 
 	pokemonList.SetChangedFunc(func(index int, mainText string, secondaryText string, shortcut rune) {
 		state.selectPokemon(mainText)
@@ -149,6 +178,11 @@ func commandTui(c *config, name ...string) error {
 		case tcell.KeyEsc:
 			app.Stop()
 			return nil
+		case tcell.KeyBackspace, tcell.KeyBackspace2:
+			if state.activeView == viewMap {
+				state.mapUp()
+			}
+			return nil
 		case tcell.KeyTab:
 			state.toggleFocus()
 			return nil
@@ -167,6 +201,11 @@ func commandTui(c *config, name ...string) error {
 					state.loadPrevLocations()
 				}
 				return nil
+			case 'b':
+				if state.activeView == viewMap {
+					state.mapUp()
+				}
+				return nil
 			case 'm':
 				state.showMap()
 				return nil
@@ -187,8 +226,8 @@ func commandTui(c *config, name ...string) error {
 		return false
 	})
 
-	state.setStatus("Loading locations...")
-	if err := state.loadLocations(nil); err != nil {
+	state.setStatus("Loading regions...")
+	if err := state.loadRegions(); err != nil {
 		return err
 	}
 	state.app.SetFocus(mapTable)
@@ -344,6 +383,10 @@ func (s *tuiState) showInspect() {
 }
 
 func (s *tuiState) loadNextLocations() {
+	if s.mapLevel != mapLevelAll {
+		s.setStatus("Paging is only for the list of all areas")
+		return
+	}
 	if s.next == nil && s.locations != nil {
 		s.setStatus("You are on the last page")
 		return
@@ -352,6 +395,10 @@ func (s *tuiState) loadNextLocations() {
 }
 
 func (s *tuiState) loadPrevLocations() {
+	if s.mapLevel != mapLevelAll {
+		s.setStatus("Paging is only for the list of all areas")
+		return
+	}
 	if s.prev == nil && s.locations != nil {
 		s.setStatus("You are on the first page")
 		return
@@ -368,24 +415,111 @@ func (s *tuiState) loadLocations(pageURL *string) error {
 
 	s.next = resp.Next
 	s.prev = resp.Previous
+	s.mapLevel = mapLevelAll
 	s.locations = make([]string, 0, len(resp.Results))
 	for _, area := range resp.Results {
 		s.locations = append(s.locations, area.Name)
 	}
+	s.showLocations("Locations loaded")
+	return nil
+}
+
+func (s *tuiState) loadRegions() error {
+	resp, err := s.config.pokeapiClient.ListRegions()
+	if err != nil {
+		s.setStatus(fmt.Sprintf("Error loading regions: %v", err))
+		return err
+	}
+	s.mapLevel = mapLevelRegions
+	s.region = ""
+	s.location = ""
+	s.locations = []string{allAreasRow}
+	for _, region := range resp.Results {
+		s.locations = append(s.locations, region.Name)
+	}
+	s.showLocations("Regions loaded, press enter to open one")
+	return nil
+}
+
+func (s *tuiState) loadRegion(region string) {
+	resp, err := s.config.pokeapiClient.GetRegion(region)
+	if err != nil {
+		s.setStatus(fmt.Sprintf("Error loading %s: %v", region, err))
+		return
+	}
+	s.mapLevel = mapLevelLocations
+	s.region = resp.Name
+	s.location = ""
+	s.locations = make([]string, 0, len(resp.Locations))
+	for _, location := range resp.Locations {
+		s.locations = append(s.locations, location.Name)
+	}
+	s.showLocations(fmt.Sprintf("Locations in %s loaded", resp.Name))
+}
+
+func (s *tuiState) loadLocationAreas(location string) {
+	resp, err := s.config.pokeapiClient.GetLocation(location)
+	if err != nil {
+		s.setStatus(fmt.Sprintf("Error loading %s: %v", location, err))
+		return
+	}
+	s.mapLevel = mapLevelAreas
+	s.location = resp.Name
+	s.locations = make([]string, 0, len(resp.Areas))
+	for _, area := range resp.Areas {
+		s.locations = append(s.locations, area.Name)
+	}
+	s.showLocations(fmt.Sprintf("Areas in %s loaded", resp.Name))
+}
 
+// openLocation drills into the row picked with enter.
+func (s *tuiState) openLocation(row int) {
+	if row < 0 || row >= len(s.locations) {
+		return
+	}
+	name := s.locations[row]
+	switch s.mapLevel {
+	case mapLevelRegions:
+		if name == allAreasRow {
+			_ = s.loadLocations(nil)
+			return
+		}
+		s.loadRegion(name)
+	case mapLevelLocations:
+		s.loadLocationAreas(name)
+	default:
+		s.app.SetFocus(s.pokemonList)
+		s.focusOnMap = false
+	}
+}
+
+// mapUp goes back one level in the map hierarchy.
+func (s *tuiState) mapUp() {
+	switch s.mapLevel {
+	case mapLevelAreas:
+		s.loadRegion(s.region)
+	case mapLevelLocations, mapLevelAll:
+		_ = s.loadRegions()
+	}
+}
+
+func (s *tuiState) showLocations(status string) {
 	s.selected = ""
+	s.pokemonList.Clear()
 	s.renderLocations()
 	if len(s.locations) == 0 {
-		s.pokemonList.Clear()
 		s.asciiView.SetText("No locations")
-		return nil
+		return
 	}
-
-	s.setStatus("Locations loaded")
-	return nil
+	s.setStatus(status)
 }
 
 func (s *tuiState) renderLocations() {
+	title := mapBreadcrumb(s.region, s.location)
+	if s.mapLevel == mapLevelAll {
+		title = mapBreadcrumb("all areas", "")
+	}
+	s.mapTable.SetTitle(title)
 	s.mapTable.Clear()
 	cols := 1
 	rows := int(math.Ceil(float64(len(s.locations)) / float64(cols)))
@@ -400,6 +534,9 @@ func (s *tuiState) renderLocations() {
 }
 
 func (s *tuiState) selectLocation(row, column int) {
+	if s.mapLevel != mapLevelAreas && s.mapLevel != mapLevelAll {
+		return
+	}
 	index := row
 	if index < 0 || index >= len(s.locations) {
 		return
@@ -421,13 +558,13 @@ func (s *tuiState) loadPokemon(area string) {
 	}
 
 	s.pokemonList.Clear()
-	if len(resp.PokemonEncounters) == 0 {
-		s.asciiView.SetText("No Pokemon found")
+	names := versionSpecies(resp, currentVersion(s.config))
+	if len(names) == 0 {
+		s.asciiView.SetText(fmt.Sprintf("No Pokemon found in %s", versionDisplayName(currentVersion(s.config))))
 		return
 	}
 
-	for _, encounter := range resp.PokemonEncounters {
-		name := encounter.Pokemon.Name
+	for _, name := range names {
 		s.pokemonList.AddItem(name, "", 0, nil)
 	}
 	s.pokemonList.SetCurrentItem(0)
@@ -439,27 +576,32 @@ func (s *tuiState) selectPokemon(name string) {
 	}
 	s.selectedPkm = name
 	s.inspectName = name
-	if cached, exists := s.spriteCache[name]; exists {
-		s.asciiView.SetText(cached)
+	s.showSprite(s.asciiView, Pokemon{name: name})
+}
+
+// showSprite renders a Pokemon's sprite as ASCII art into view.
+func (s *tuiState) showSprite(view *tview.TextView, pokemon Pokemon) {
+	key := strings.Join([]string{pokemon.name, pokemon.form, pokemon.gender, strconv.FormatBool(pokemon.shiny)}, ":")
+	if cached, exists := s.spriteCache[key]; exists {
+		view.SetText(cached)
 		return
 	}
 
-	s.setStatus(fmt.Sprintf("Loading sprite for %s...", name))
-	poke, err := s.config.pokeapiClient.GetPokemon(name)
+	s.setStatus(fmt.Sprintf("Loading sprite for %s...", pokemon.name))
+	url, err := spriteURLForPokemon(s.config, pokemon)
 	if err != nil {
 		s.setStatus(fmt.Sprintf("Error loading sprite: %v", err))
 		return
 	}
 
-	url := spriteURLFromPokemon(poke)
 	art, err := fetchSpriteASCII(url)
 	if err != nil {
 		s.setStatus(fmt.Sprintf("Error converting sprite: %v", err))
 		return
 	}
 
-	s.spriteCache[name] = art
-	s.asciiView.SetText(art)
+	s.spriteCache[key] = art
+	view.SetText(art)
 	s.setStatus("Sprite loaded")
 }
 
@@ -503,14 +645,16 @@ func (s *tuiState) renderPokedexSummary(name string) {
 		return
 	}
 	poke := entries[len(entries)-1]
+	s.showSprite(s.dexSprite, poke)
 
 	var b strings.Builder
-	fmt.Fprintf(&b, "Name: %s\n", poke.name)
+	fmt.Fprintf(&b, "Name: %s%s\n", poke.name, shinyMarker(poke))
 	fmt.Fprintf(&b, "Caught: %s\n", poke.dateCaught.Format("2006-01-02 15:04"))
 	fmt.Fprintf(&b, "ID: %d\n", poke.id)
 	if poke.species != "" {
 		fmt.Fprintf(&b, "Species: %s\n", poke.species)
 	}
+	fmt.Fprintf(&b, "HP: %s\n", hpLabel(poke))
 	fmt.Fprintf(&b, "Base XP: %d\n", poke.baseExperience)
 	fmt.Fprintf(&b, "Height: %d\n", poke.height)
 	fmt.Fprintf(&b, "Weight: %d\n", poke.weight)
@@ -554,7 +698,7 @@ func (s *tuiState) renderInspect(name string) {
 	poke := entries[len(entries)-1]
 
 	var b strings.Builder
-	fmt.Fprintf(&b, "Name: %s\n", poke.name)
+	fmt.Fprintf(&b, "Name: %s%s\n", poke.name, shinyMarker(poke))
 	fmt.Fprintf(&b, "ID: %d\n", poke.id)
 	if poke.species != "" {
 		fmt.Fprintf(&b, "Species: %s\n", poke.species)
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+func commandVersion(c *config, name ...string) error {
+	if len(name) > 1 {
+		return errors.New("Command version takes a single game version")
+	}
+	if len(name) == 0 {
+		fmt.Println()
+		fmt.Printf("Playing: %s\n", versionDisplayName(currentVersion(c)))
+		fmt.Printf("Versions: %s\n", strings.Join(versionOrder, ", "))
+		fmt.Println()
+		return nil
+	}
+
+	version, known := parseVersion(name[0])
+	if !known {
+		return fmt.Errorf("Unknown version %s, enter version to list them", name[0])
+	}
+	// Few regions are joined by routes, so a new region means setting out
+	// from its starting town.
+	newRegion := gameVersions[version].region != versionRegion(c)
+	c.Version = version
+	if newRegion {
+		c.Location, c.Area = "", ""
+	}
+	// Level ranges from the last area belong to the old version.
+	c.encounters = nil
+	if err := saveUserData(c); err != nil {
+		return err
+	}
+	fmt.Printf("Now playing %s. Pokemon you already have keep their moves.\n", versionDisplayName(version))
+	if newRegion {
+		fmt.Printf("You set out from %s.\n", currentLocation(c))
+	}
+	return nil
+}
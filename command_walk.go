@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dey12956/pokedexcli/internal/pokeapi"
+)
+
+// encounterSearch is one way of looking for wild Pokemon in an area.
+type encounterSearch struct {
+	methods []string
+	tries   int
+	// empty is printed when the area has nothing to find this way, and
+	// miss when nothing turns up this time.
+	empty string
+	miss  string
+}
+
+func commandWalk(c *config, name ...string) error {
+	if len(name) > 1 {
+		return errors.New("Command walk takes a single area")
+	}
+	area := c.Area
+	if len(name) == 1 {
+		area = name[0]
+	}
+	if area == "" {
+		return errors.New("Enter an area to walk through")
+	}
+
+	fmt.Println()
+	fmt.Printf("Walking through %s...\n", area)
+
+	resp, err := visitArea(c, area)
+	if err != nil {
+		return err
+	}
+	return searchArea(c, resp, encounterSearch{
+		methods: []string{methodWalk},
+		tries:   walkSteps,
+		empty:   "There's no tall grass here to walk through.",
+		miss:    "Nothing appeared.",
+	})
+}
+
+// searchArea looks for a wild Pokemon in an area and battles it if one
+// turns up.
+func searchArea(c *config, resp pokeapi.PokemonResponse, search encounterSearch) error {
+	version := currentVersion(c)
+	slots := slotsForMethods(encounterSlots(resp, version), search.methods...)
+	if len(slots) == 0 {
+		fmt.Println(search.empty)
+		fmt.Println()
+		return nil
+	}
+	slot, ok := searchSlots(rng, resp, version, slots, search.tries)
+	if !ok {
+		fmt.Println(search.miss)
+		fmt.Println()
+		return nil
+	}
+	fmt.Println()
+	return battleWild(c, slot.species, slot.levels)
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/dey12956/pokedexcli/internal/pokeapi"
+)
+
+// levelRange is the span of levels a species can be met at in an area.
+type levelRange struct {
+	min int
+	max int
+}
+
+func (l levelRange) String() string {
+	if l.max <= l.min {
+		return fmt.Sprintf("(Lv %d)", l.min)
+	}
+	return fmt.Sprintf("(Lv %d-%d)", l.min, l.max)
+}
+
+func (l levelRange) roll(r *rand.Rand) int {
+	if l.max <= l.min {
+		return l.min
+	}
+	return l.min + r.Intn(l.max-l.min+1)
+}
+
+// encounterLevels collects the level range of each species found in an
+// area in one version, across every encounter method.
+func encounterLevels(resp pokeapi.PokemonResponse, version string) map[string]levelRange {
+	levels := make(map[string]levelRange, len(resp.PokemonEncounters))
+	for _, encounter := range resp.PokemonEncounters {
+		span, found := levelRange{}, false
+		for _, details := range encounter.VersionDetails {
+			if details.Version.Name != version {
+				continue
+			}
+			for _, detail := range details.EncounterDetails {
+				if detail.MinLevel < 1 {
+					continue
+				}
+				high := max(detail.MaxLevel, detail.MinLevel)
+				if !found {
+					span, found = levelRange{min: detail.MinLevel, max: high}, true
+					continue
+				}
+				span.min = min(span.min, detail.MinLevel)
+				span.max = max(span.max, high)
+			}
+		}
+		if found {
+			levels[encounter.Pokemon.Name] = span
+		}
+	}
+	return levels
+}
+
+// setWildLevel moves a freshly built wild Pokemon to the level it was
+// encountered at, with the experience, stats and moves to match.
+func setWildLevel(c *config, pokemon *Pokemon, level int) error {
+	level = max(1, min(level, maxLevel))
+	experience, err := experienceForLevel(c, pokemon.growthRate, level)
+	if err != nil {
+		return err
+	}
+	pokemon.experience = experience
+	setLevel(pokemon, level)
+	if err := resetMoves(c, pokemon); err != nil {
+		return err
+	}
+	restoreHP(pokemon)
+	return nil
+}
+
+const (
+	methodWalk      = "walk"
+	methodOldRod    = "old-rod"
+	methodGoodRod   = "good-rod"
+	methodSuperRod  = "super-rod"
+	methodSurf      = "surf"
+	methodRockSmash = "rock-smash"
+	methodHeadbutt  = "headbutt"
+)
+
+// walkSteps is how many steps a walk or surf takes looking for an encounter.
+const walkSteps = 10
+
+// headbuttMethods covers the tree types some games split headbutt into.
+var headbuttMethods = []string{methodHeadbutt, "headbutt-low", "headbutt-normal", "headbutt-high"}
+
+// encounterSlot is one way to meet a species in an area: by a method, with
+// a chance out of that method's total and a level range.
+type encounterSlot struct {
+	species string
+	method  string
+	chance  int
+	levels  levelRange
+}
+
+// versionSpecies lists the species met in an area in one version, in the
+// order the area lists them.
+func versionSpecies(resp pokeapi.PokemonResponse, version string) []string {
+	names := make([]string, 0, len(resp.PokemonEncounters))
+	for _, encounter := range resp.PokemonEncounters {
+		for _, details := range encounter.VersionDetails {
+			if details.Version.Name == version {
+				names = append(names, encounter.Pokemon.Name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// areaVersions lists the versions an area has encounters in, in release
+// order.
+func areaVersions(resp pokeapi.PokemonResponse) []string {
+	found := make(map[string]bool)
+	for _, encounter := range resp.PokemonEncounters {
+		for _, details := range encounter.VersionDetails {
+			found[details.Version.Name] = true
+		}
+	}
+	versions := make([]string, 0, len(found))
+	for _, version := range versionOrder {
+		if found[version] {
+			versions = append(versions, version)
+		}
+	}
+	return versions
+}
+
+// encounterSlots lists every encounter in an area for one version.
+func encounterSlots(resp pokeapi.PokemonResponse, version string) []encounterSlot {
+	slots := make([]encounterSlot, 0)
+	for _, encounter := range resp.PokemonEncounters {
+		for _, details := range encounter.VersionDetails {
+			if details.Version.Name != version {
+				continue
+			}
+			for _, detail := range details.EncounterDetails {
+				if detail.Chance <= 0 {
+					continue
+				}
+				slots = append(slots, encounterSlot{
+					species: encounter.Pokemon.Name,
+					method:  detail.Method.Name,
+					chance:  detail.Chance,
+					levels:  levelRange{min: max(detail.MinLevel, 1), max: max(detail.MaxLevel, detail.MinLevel, 1)},
+				})
+			}
+		}
+	}
+	return slots
+}
+
+func slotsForMethods(slots []encounterSlot, methods ...string) []encounterSlot {
+	matched := make([]encounterSlot, 0, len(slots))
+	for _, slot := range slots {
+		if slices.Contains(methods, slot.method) {
+			matched = append(matched, slot)
+		}
+	}
+	return matched
+}
+
+// rollEncounter picks a slot weighted by its chance.
+func rollEncounter(r *rand.Rand, slots []encounterSlot) (encounterSlot, bool) {
+	total := 0
+	for _, slot := range slots {
+		total += slot.chance
+	}
+	if total <= 0 {
+		return encounterSlot{}, false
+	}
+	roll := r.Intn(total)
+	for _, slot := range slots {
+		if roll < slot.chance {
+			return slot, true
+		}
+		roll -= slot.chance
+	}
+	return slots[len(slots)-1], true
+}
+
+// searchSlots makes up to tries attempts at meeting a Pokemon, each one
+// succeeding at the encounter rate of the rolled slot's method.
+func searchSlots(r *rand.Rand, resp pokeapi.PokemonResponse, version string, slots []encounterSlot, tries int) (encounterSlot, bool) {
+	for range tries {
+		slot, ok := rollEncounter(r, slots)
+		if !ok {
+			return encounterSlot{}, false
+		}
+		if r.Intn(100) < encounterRate(resp, version, slot.method) {
+			return slot, true
+		}
+	}
+	return encounterSlot{}, false
+}
+
+// encounterChances sums each species' share of every method in percent.
+func encounterChances(slots []encounterSlot) map[string]map[string]int {
+	totals := make(map[string]int)
+	for _, slot := range slots {
+		totals[slot.method] += slot.chance
+	}
+	chances := make(map[string]map[string]int)
+	for _, slot := range slots {
+		if chances[slot.species] == nil {
+			chances[slot.species] = make(map[string]int)
+		}
+		chances[slot.species][slot.method] += slot.chance
+	}
+	for _, methods := range chances {
+		for method, chance := range methods {
+			methods[method] = max(1, chance*100/totals[method])
+		}
+	}
+	return chances
+}
+
+// encounterRate is the percent chance per step of meeting a Pokemon by
+// method. Areas without rate data always have an encounter.
+func encounterRate(resp pokeapi.PokemonResponse, version, method string) int {
+	for _, rate := range resp.EncounterMethodRates {
+		if rate.EncounterMethod.Name != method {
+			continue
+		}
+		for _, details := range rate.VersionDetails {
+			if details.Version.Name == version && details.Rate > 0 {
+				return min(details.Rate, 100)
+			}
+		}
+	}
+	return 100
+}
+
+func formatChances(methods map[string]int) string {
+	names := make([]string, 0, len(methods))
+	for method := range methods {
+		names = append(names, method)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, method := range names {
+		parts = append(parts, fmt.Sprintf("%s %d%%", method, methods[method]))
+	}
+	return strings.Join(parts, ", ")
+}
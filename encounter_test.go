@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	"github.com/dey12956/pokedexcli/internal/pokeapi"
+)
+
+func TestEncounterLevelsFollowVersion(t *testing.T) {
+	payload := `{"pokemon_encounters":[
+		{"pokemon":{"name":"pidgey"},"version_details":[
+			{"version":{"name":"red"},"encounter_details":[{"min_level":2,"max_level":4},{"min_level":3,"max_level":5}]},
+			{"version":{"name":"gold"},"encounter_details":[{"min_level":2,"max_level":2}]}
+		]},
+		{"pokemon":{"name":"magikarp"},"version_details":[
+			{"version":{"name":"red"},"encounter_details":[{"min_level":5,"max_level":0}]}
+		]},
+		{"pokemon":{"name":"missingno"},"version_details":[]}
+	]}`
+	var resp pokeapi.PokemonResponse
+	if err := json.Unmarshal([]byte(payload), &resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	levels := encounterLevels(resp, "red")
+	if levels["pidgey"] != (levelRange{min: 2, max: 5}) {
+		t.Fatalf("expected pidgey at 2-5, got %+v", levels["pidgey"])
+	}
+	if levels["magikarp"] != (levelRange{min: 5, max: 5}) {
+		t.Fatalf("expected magikarp at 5, got %+v", levels["magikarp"])
+	}
+	if _, ok := levels["missingno"]; ok {
+		t.Fatalf("expected no range without encounter details")
+	}
+	if label := levels["pidgey"].String(); label != "(Lv 2-5)" {
+		t.Fatalf("unexpected label %q", label)
+	}
+	gold := encounterLevels(resp, "gold")
+	if len(gold) != 1 || gold["pidgey"] != (levelRange{min: 2, max: 2}) {
+		t.Fatalf("expected only pidgey at 2 in gold, got %+v", gold)
+	}
+	if species := versionSpecies(resp, "gold"); len(species) != 1 || species[0] != "pidgey" {
+		t.Fatalf("expected only pidgey listed in gold, got %v", species)
+	}
+	if versions := areaVersions(resp); len(versions) != 2 || versions[0] != "red" || versions[1] != "gold" {
+		t.Fatalf("expected red and gold in release order, got %v", versions)
+	}
+}
+
+func TestLevelRangeRollStaysInRange(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	span := levelRange{min: 2, max: 5}
+	seen := make(map[int]bool)
+	for range 200 {
+		level := span.roll(r)
+		if level < span.min || level > span.max {
+			t.Fatalf("rolled level %d outside %+v", level, span)
+		}
+		seen[level] = true
+	}
+	if len(seen) != 4 {
+		t.Fatalf("expected every level in range to come up, got %v", seen)
+	}
+}
+
+func TestRollEncounterWeightsByChance(t *testing.T) {
+	payload := `{"encounter_method_rates":[
+		{"encounter_method":{"name":"walk"},"version_details":[{"rate":25,"version":{"name":"red"}}]}
+	],"pokemon_encounters":[
+		{"pokemon":{"name":"pidgey"},"version_details":[
+			{"version":{"name":"red"},"encounter_details":[{"chance":95,"min_level":2,"max_level":4,"method":{"name":"walk"}}]}
+		]},
+		{"pokemon":{"name":"pikachu"},"version_details":[
+			{"version":{"name":"red"},"encounter_details":[{"chance":5,"min_level":3,"max_level":5,"method":{"name":"walk"}}]}
+		]},
+		{"pokemon":{"name":"magikarp"},"version_details":[
+			{"version":{"name":"red"},"encounter_details":[{"chance":100,"min_level":5,"max_level":5,"method":{"name":"old-rod"}}]},
+			{"version":{"name":"gold"},"encounter_details":[{"chance":100,"min_level":10,"max_level":10,"method":{"name":"walk"}}]}
+		]}
+	]}`
+	var resp pokeapi.PokemonResponse
+	if err := json.Unmarshal([]byte(payload), &resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	version := currentVersion(&config{})
+	if version != "red" {
+		t.Fatalf("expected red by default, got %q", version)
+	}
+	if rate := encounterRate(resp, version, methodWalk); rate != 25 {
+		t.Fatalf("expected a walk rate of 25, got %d", rate)
+	}
+	if rate := encounterRate(resp, version, "surf"); rate != 100 {
+		t.Fatalf("expected a missing rate to always encounter, got %d", rate)
+	}
+
+	slots := encounterSlots(resp, version)
+	chances := encounterChances(slots)
+	if chances["pikachu"][methodWalk] != 5 || chances["magikarp"]["old-rod"] != 100 || chances["magikarp"][methodWalk] != 0 {
+		t.Fatalf("unexpected chances %v", chances)
+	}
+	if label := formatChances(chances["magikarp"]); label != "old-rod 100%" {
+		t.Fatalf("unexpected label %q", label)
+	}
+
+	walking := slotsForMethods(slots, methodWalk)
+	r := rand.New(rand.NewSource(8))
+	counts := make(map[string]int)
+	for range 2000 {
+		slot, ok := rollEncounter(r, walking)
+		if !ok {
+			t.Fatalf("expected an encounter")
+		}
+		counts[slot.species]++
+	}
+	if counts["magikarp"] != 0 {
+		t.Fatalf("expected no magikarp while walking in red, got %d", counts["magikarp"])
+	}
+	if counts["pikachu"] < 50 || counts["pikachu"] > 170 {
+		t.Fatalf("expected pikachu about 5%% of the time, got %d of 2000", counts["pikachu"])
+	}
+	if _, ok := rollEncounter(r, nil); ok {
+		t.Fatalf("expected no encounter without slots")
+	}
+}
+
+func TestSearchSlotsUsesMethodRate(t *testing.T) {
+	payload := `{"encounter_method_rates":[
+		{"encounter_method":{"name":"surf"},"version_details":[{"rate":1,"version":{"name":"red"}}]}
+	],"pokemon_encounters":[
+		{"pokemon":{"name":"tentacool"},"version_details":[
+			{"version":{"name":"red"},"encounter_details":[{"chance":100,"min_level":5,"max_level":40,"method":{"name":"surf"}}]}
+		]},
+		{"pokemon":{"name":"poliwag"},"version_details":[
+			{"version":{"name":"red"},"encounter_details":[{"chance":100,"min_level":10,"max_level":10,"method":{"name":"good-rod"}}]}
+		]}
+	]}`
+	var resp pokeapi.PokemonResponse
+	if err := json.Unmarshal([]byte(payload), &resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	slots := encounterSlots(resp, "red")
+	r := rand.New(rand.NewSource(3))
+	if slot, ok := searchSlots(r, resp, "red", slotsForMethods(slots, methodGoodRod), 1); !ok || slot.species != "poliwag" {
+		t.Fatalf("expected poliwag on the good rod, got %+v", slot)
+	}
+	found := 0
+	for range 100 {
+		if _, ok := searchSlots(r, resp, "red", slotsForMethods(slots, methodSurf), 1); ok {
+			found++
+		}
+	}
+	if found > 10 {
+		t.Fatalf("expected a 1%% surf rate to rarely find anything, found %d in 100", found)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// trapUntilSwitch marks a trap that lasts until the trapped Pokemon leaves
+// the field (Mean Look and friends) rather than for a number of turns.
+const trapUntilSwitch = -1
+
+// bindingMoves hold the target in place for a few turns; blockingMoves
+// until it switches out.
+var (
+	bindingMoves  = map[string]bool{"bind": true, "wrap": true, "fire-spin": true, "clamp": true, "whirlpool": true, "sand-tomb": true, "magma-storm": true, "infestation": true}
+	blockingMoves = map[string]bool{"mean-look": true, "block": true, "spider-web": true}
+)
+
+// escapeBlocker reports why runner can't flee from foe, or "" if it may try.
+// Ghost types and Pokemon with Run Away are never held in place.
+func escapeBlocker(runner, foe *battlePokemon) string {
+	if hasType(runner.pokemon, "ghost") || hasAbility(runner.pokemon, "run-away") {
+		return ""
+	}
+	if runner.trapTurns != 0 {
+		return fmt.Sprintf("%s can't escape, it's trapped!", runner.displayName())
+	}
+	if foe == nil {
+		return ""
+	}
+	ability := ""
+	switch {
+	case hasAbility(foe.pokemon, "shadow-tag") && !hasAbility(runner.pokemon, "shadow-tag"):
+		ability = "Shadow Tag"
+	case hasAbility(foe.pokemon, "arena-trap") && !hasType(runner.pokemon, "flying") && !hasAbility(runner.pokemon, "levitate"):
+		ability = "Arena Trap"
+	case hasAbility(foe.pokemon, "magnet-pull") && hasType(runner.pokemon, "steel"):
+		ability = "Magnet Pull"
+	}
+	if ability != "" {
+		return fmt.Sprintf("%s's %s prevents escape!", foe.displayName(), ability)
+	}
+	return ""
+}
+
+// escapeOdds is the chance out of 256 that runner gets away on the given
+// attempt (1 for the first try). A runner at least as fast as the foe always
+// escapes and every attempt makes the next one likelier.
+func escapeOdds(runner, foe battlePokemon, attempt int) int {
+	if hasAbility(runner.pokemon, "run-away") || hasType(runner.pokemon, "ghost") {
+		return 256
+	}
+	runnerSpeed := effectiveStat(runner, "speed")
+	foeSpeed := effectiveStat(foe, "speed")
+	if runnerSpeed >= foeSpeed {
+		return 256
+	}
+	return min(256, runnerSpeed*128/foeSpeed+30*attempt)
+}
+
+// attemptEscape rolls an escape for runner. The reason is set when the
+// attempt failed or wasn't allowed at all (blocked is true).
+func attemptEscape(r *rand.Rand, runner, foe *battlePokemon, attempt int) (escaped, blocked bool, reason string) {
+	if reason := escapeBlocker(runner, foe); reason != "" {
+		return false, true, reason
+	}
+	if foe == nil || r.Intn(256) < escapeOdds(*runner, *foe, attempt) {
+		return true, false, ""
+	}
+	return false, false, "Can't escape!"
+}
+
+// applyTrap holds defender in place after being hit by a trapping move.
+func applyTrap(r *rand.Rand, attacker, defender *battlePokemon, move PokemonMove) []battleEvent {
+	if defender.current <= 0 || defender.trapTurns != 0 {
+		return nil
+	}
+	switch {
+	case bindingMoves[move.name]:
+		defender.trapTurns = 4 + r.Intn(2)
+	case blockingMoves[move.name]:
+		defender.trapTurns = trapUntilSwitch
+	default:
+		return nil
+	}
+	return []battleEvent{{
+		Kind:    eventStatus,
+		Side:    defender.side,
+		Pokemon: defender.pokemon.name,
+		HP:      defender.current,
+		Message: fmt.Sprintf("%s was trapped by %s!", defender.displayName(), attacker.displayName()),
+	}}
+}
+
+// tickTrap counts down a binding trap at the end of a round.
+func (p *battlePokemon) tickTrap() {
+	if p.trapTurns > 0 {
+		p.trapTurns--
+	}
+}
+
+func hasType(pokemon Pokemon, name string) bool {
+	for _, t := range pokemon.types {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAbility(pokemon Pokemon, name string) bool {
+	for _, ability := range pokemon.abilities {
+		if ability.name == name {
+			return true
+		}
+	}
+	return false
+}
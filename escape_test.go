@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func escapeTestPokemon(name string, speed int, types ...string) *battlePokemon {
+	battle := newBattlePokemon(Pokemon{name: name, level: 10, types: types, stats: map[string]int{"hp": 40, "speed": speed}, currentHP: 30})
+	return &battle
+}
+
+func TestEscapeOddsGrowWithSpeedAndAttempts(t *testing.T) {
+	slow := escapeTestPokemon("slowpoke", 15, "water")
+	fast := escapeTestPokemon("jolteon", 130, "electric")
+	if odds := escapeOdds(*fast, *slow, 1); odds != 256 {
+		t.Fatalf("expected a faster runner to always escape, got %d", odds)
+	}
+	first := escapeOdds(*slow, *fast, 1)
+	if first >= 256 || escapeOdds(*slow, *fast, 3) <= first {
+		t.Fatalf("expected odds to start low and grow with attempts, got %d", first)
+	}
+	if escapeOdds(*slow, *fast, 10) != 256 {
+		t.Fatalf("expected enough attempts to guarantee escape")
+	}
+}
+
+func TestEscapeBlockedByTrapsAndAbilities(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	runner := escapeTestPokemon("pidgey", 200, "normal", "flying")
+	foe := escapeTestPokemon("dugtrio", 120, "ground")
+	foe.pokemon.abilities = []pokemonAbility{{name: "arena-trap"}}
+	if escaped, blocked, _ := attemptEscape(r, runner, foe, 1); !escaped || blocked {
+		t.Fatalf("expected a flying Pokemon to ignore Arena Trap")
+	}
+
+	foe.pokemon.abilities = []pokemonAbility{{name: "shadow-tag"}}
+	if _, blocked, reason := attemptEscape(r, runner, foe, 1); !blocked || reason == "" {
+		t.Fatalf("expected Shadow Tag to block escape")
+	}
+
+	foe.pokemon.abilities = nil
+	events := applyTrap(r, foe, runner, PokemonMove{name: "mean-look"})
+	if len(events) != 1 || runner.trapTurns != trapUntilSwitch {
+		t.Fatalf("expected mean look to trap, got %+v", events)
+	}
+	if _, blocked, _ := attemptEscape(r, runner, foe, 1); !blocked {
+		t.Fatalf("expected a trapped Pokemon to be unable to run")
+	}
+
+	ghost := escapeTestPokemon("gastly", 1, "ghost", "poison")
+	ghost.trapTurns = 3
+	if escaped, _, _ := attemptEscape(r, ghost, foe, 1); !escaped {
+		t.Fatalf("expected a ghost type to always escape")
+	}
+
+	side := newBattleSide(sidePlayer, "", []Pokemon{runner.pokemon, foe.pokemon})
+	side.active = 0
+	side.team[0].trapTurns = 2
+	side.bringIn(1)
+	if side.team[0].trapTurns != 0 {
+		t.Fatalf("expected switching out to clear the trap")
+	}
+}
+
+func TestMeanLookTrapsWithoutDamage(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	umbreon := escapeTestPokemon("umbreon", 65, "dark")
+	runner := escapeTestPokemon("rattata", 72, "normal")
+	meanLook := PokemonMove{name: "mean-look", moveType: "normal", damageClass: "status", target: "selected-pokemon"}
+	before := runner.current
+	events := resolveAttack(r, umbreon, runner, meanLook)
+	if runner.current != before {
+		t.Fatalf("expected mean look to deal no damage, HP went %d -> %d", before, runner.current)
+	}
+	if runner.trapTurns != trapUntilSwitch {
+		t.Fatalf("expected mean look to trap, got %+v", events)
+	}
+	if len(events) != 2 || events[1].Kind != eventStatus {
+		t.Fatalf("expected an attack and a trap event, got %+v", events)
+	}
+}
+
+func TestBlockedRunDoesNotUseTheTurn(t *testing.T) {
+	runner := escapeTestPokemon("pidgey", 200, "normal", "flying")
+	foe := escapeTestPokemon("ekans", 50, "poison")
+	c := &config{}
+	b := newBattleSession(c, newBattleRecorder(c, 1), 1, []Pokemon{runner.pokemon})
+	b.reader = bufio.NewReader(strings.NewReader("3\n3\n3\n3\n3\n3\n"))
+	b.player.bringIn(0)
+	b.player.current().trapTurns = 4
+	b.foe = newBattleSide(sideWild, "Wild ", []Pokemon{foe.pokemon})
+	b.foe.active = 0
+
+	if err := b.run(); err == nil {
+		t.Fatalf("expected the battle to stop when input runs out")
+	}
+	if turns := b.player.current().trapTurns; turns != 4 {
+		t.Fatalf("expected blocked runs to leave the trap at 4 turns, got %d", turns)
+	}
+	if b.rec.round != 1 {
+		t.Fatalf("expected blocked runs to stay in round 1, got %d", b.rec.round)
+	}
+}
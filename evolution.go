@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dey12956/pokedexcli/internal/pokeapi"
+)
+
+const (
+	evolutionTriggerLevelUp = "level-up"
+	evolutionTriggerItem    = "use-item"
+	evolutionTriggerTrade   = "trade"
+)
+
+// tradeItem stands in for a trade, since trainers can't trade Pokemon.
+const tradeItem = "linking-cord"
+
+// evolutionContext is what's happening when an evolution is checked: which
+// trigger fired and the item, place and time it fired with.
+type evolutionContext struct {
+	trigger  string
+	item     string
+	location string
+	now      time.Time
+}
+
+// evolutionOption is one branch a Pokemon can evolve into right now; item
+// is the bag item the evolution uses up, if any.
+type evolutionOption struct {
+	species string
+	detail  pokeapi.EvolutionDetail
+	item    string
+}
+
+// describe explains the conditions of an evolution, e.g. "level 16" or
+// "thunder-stone".
+func (o evolutionOption) describe() string {
+	detail := o.detail
+	parts := make([]string, 0, 4)
+	switch detail.Trigger.Name {
+	case evolutionTriggerItem:
+		if detail.Item != nil {
+			parts = append(parts, detail.Item.Name)
+		}
+	case evolutionTriggerTrade:
+		parts = append(parts, "trade")
+		if o.item != "" {
+			parts = append(parts, "with a "+o.item)
+		}
+	}
+	if detail.MinLevel != nil {
+		parts = append(parts, fmt.Sprintf("level %d", *detail.MinLevel))
+	}
+	if detail.MinHappiness != nil {
+		parts = append(parts, fmt.Sprintf("friendship %d", *detail.MinHappiness))
+	}
+	if detail.TimeOfDay != "" {
+		parts = append(parts, "at "+detail.TimeOfDay)
+	}
+	if detail.KnownMove != nil {
+		parts = append(parts, "knowing "+detail.KnownMove.Name)
+	}
+	if detail.KnownMoveType != nil {
+		parts = append(parts, "knowing a "+detail.KnownMoveType.Name+" move")
+	}
+	if detail.HeldItem != nil {
+		parts = append(parts, "holding "+detail.HeldItem.Name)
+	}
+	if detail.Location != nil {
+		parts = append(parts, "at "+detail.Location.Name)
+	}
+	if detail.Gender != nil {
+		parts = append(parts, "if "+apiGenderName(*detail.Gender))
+	}
+	if len(parts) == 0 {
+		parts = append(parts, detail.Trigger.Name)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// findEvolutions returns the chain links a species can evolve into.
+func findEvolutions(link pokeapi.EvolutionChainLink, species string) []pokeapi.EvolutionChainLink {
+	if strings.EqualFold(link.Species.Name, species) {
+		return link.EvolvesTo
+	}
+	for _, evolve := range link.EvolvesTo {
+		if found := findEvolutions(evolve, species); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// eligibleEvolutions lists every branch whose conditions pokemon meets in
+// ctx, one option per branch.
+func eligibleEvolutions(chain pokeapi.EvolutionChainLink, pokemon Pokemon, ctx evolutionContext) []evolutionOption {
+	options := make([]evolutionOption, 0)
+	for _, evolve := range findEvolutions(chain, pokemon.species) {
+		for _, detail := range evolve.EvolutionDetails {
+			if evolutionConditionsMet(pokemon, detail, ctx) {
+				options = append(options, evolutionOption{species: evolve.Species.Name, detail: detail, item: ctx.item})
+				break
+			}
+		}
+	}
+	return options
+}
+
+// itemEvolutionContext is using item on a Pokemon. The linking cord counts
+// as a trade.
+func itemEvolutionContext(c *config, item string) evolutionContext {
+	trigger := evolutionTriggerItem
+	if item == tradeItem {
+		trigger = evolutionTriggerTrade
+	}
+	return evolutionContext{trigger: trigger, item: item, location: currentLocation(c), now: time.Now()}
+}
+
+func evolutionConditionsMet(pokemon Pokemon, detail pokeapi.EvolutionDetail, ctx evolutionContext) bool {
+	if detail.Trigger.Name != ctx.trigger {
+		return false
+	}
+	switch {
+	case ctx.trigger == evolutionTriggerItem && (detail.Item == nil || detail.Item.Name != ctx.item):
+		return false
+	case detail.MinLevel != nil && pokemon.level < *detail.MinLevel:
+		return false
+	case detail.MinHappiness != nil && pokemon.friendship < *detail.MinHappiness:
+		return false
+	case detail.TimeOfDay != "" && !isTimeOfDay(ctx.now, detail.TimeOfDay):
+		return false
+	case detail.KnownMove != nil && !knowsMove(pokemon, detail.KnownMove.Name):
+		return false
+	case detail.KnownMoveType != nil && !knowsMoveOfType(pokemon, detail.KnownMoveType.Name):
+		return false
+	case detail.HeldItem != nil && !contains(pokemon.heldItems, detail.HeldItem.Name):
+		return false
+	case detail.Location != nil && detail.Location.Name != ctx.location:
+		return false
+	case detail.Gender != nil && !matchesAPIGender(pokemon.gender, *detail.Gender):
+		return false
+	}
+	return true
+}
+
+// isTimeOfDay treats 6:00-17:59 as day, with the last hour also counting as
+// dusk, and the rest as night.
+func isTimeOfDay(now time.Time, timeOfDay string) bool {
+	hour := now.Hour()
+	switch timeOfDay {
+	case "day":
+		return hour >= 6 && hour < 18
+	case "dusk":
+		return hour == 17
+	case "night":
+		return hour < 6 || hour >= 18
+	}
+	return false
+}
+
+func knowsMoveOfType(pokemon Pokemon, moveType string) bool {
+	for _, move := range pokemon.moves {
+		if move.moveType == moveType {
+			return true
+		}
+	}
+	return false
+}
+
+// maybeEvolve checks level-up evolutions after pokemon gained a level. The
+// player picks a branch when several are possible and can stop the
+// evolution.
+func maybeEvolve(c *config, pokemon *Pokemon) error {
+	if pokemon == nil || pokemon.evolutionChain == "" || pokemon.species == "" {
+		return nil
+	}
+	chain, err := c.pokeapiClient.GetEvolutionChain(pokemon.evolutionChain)
+	if err != nil {
+		return err
+	}
+	ctx := evolutionContext{trigger: evolutionTriggerLevelUp, location: currentLocation(c), now: time.Now()}
+	options := eligibleEvolutions(chain.Chain, *pokemon, ctx)
+	if len(options) == 0 {
+		return nil
+	}
+	choice, err := chooseEvolution(c, *pokemon, options)
+	if err != nil {
+		return err
+	}
+	if choice < 0 {
+		fmt.Printf("%s stopped evolving.\n", pokemon.name)
+		return nil
+	}
+	previous := pokemon.name
+	if err := evolveInto(c, pokemon, options[choice].species); err != nil {
+		return err
+	}
+	fmt.Printf("%s evolved into %s!\n", previous, pokemon.name)
+	return nil
+}
+
+func chooseEvolution(c *config, pokemon Pokemon, options []evolutionOption) (int, error) {
+	choose := c.chooseEvolution
+	if choose == nil {
+		choose = promptEvolution
+	}
+	return choose(pokemon, options)
+}
+
+// promptEvolution asks which evolution to take; -1 cancels it.
+func promptEvolution(pokemon Pokemon, options []evolutionOption) (int, error) {
+	fmt.Printf("\nWhat? %s is evolving!\n", pokemon.name)
+	for i, option := range options {
+		fmt.Printf("%d) Evolve into %s (%s)\n", i+1, option.species, option.describe())
+	}
+	fmt.Printf("%d) Stop the evolution\n", len(options)+1)
+	choice, cancelled, err := promptChoice(bufio.NewReader(os.Stdin), "Evolution > ", len(options)+1)
+	if err != nil {
+		return -1, err
+	}
+	if cancelled || choice > len(options) {
+		return -1, nil
+	}
+	return choice - 1, nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/dey12956/pokedexcli/internal/pokeapi"
+)
+
+func evolutionTestChain(t *testing.T) pokeapi.EvolutionChainLink {
+	t.Helper()
+	payload := `{"species":{"name":"eevee"},"evolves_to":[
+		{"species":{"name":"vaporeon"},"evolution_details":[{"trigger":{"name":"use-item"},"item":{"name":"water-stone"}}]},
+		{"species":{"name":"espeon"},"evolution_details":[{"trigger":{"name":"level-up"},"min_happiness":160,"time_of_day":"day"}]},
+		{"species":{"name":"sylveon"},"evolution_details":[{"trigger":{"name":"level-up"},"known_move_type":{"name":"fairy"}}]},
+		{"species":{"name":"leafeon"},"evolution_details":[{"trigger":{"name":"level-up"},"location":{"name":"eterna-forest"}}]},
+		{"species":{"name":"politoed"},"evolution_details":[{"trigger":{"name":"trade"},"held_item":{"name":"kings-rock"}}]}
+	]}`
+	var chain pokeapi.EvolutionChainLink
+	if err := json.Unmarshal([]byte(payload), &chain); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return chain
+}
+
+func evolutionSpecies(options []evolutionOption) []string {
+	names := make([]string, 0, len(options))
+	for _, option := range options {
+		names = append(names, option.species)
+	}
+	return names
+}
+
+func TestEligibleEvolutionsChecksConditions(t *testing.T) {
+	chain := evolutionTestChain(t)
+	noon := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	eevee := Pokemon{name: "eevee", species: "eevee", level: 20}
+
+	levelUp := evolutionContext{trigger: evolutionTriggerLevelUp, now: noon}
+	if options := eligibleEvolutions(chain, eevee, levelUp); len(options) != 0 {
+		t.Fatalf("expected no level-up evolutions, got %v", evolutionSpecies(options))
+	}
+
+	eevee.moves = []PokemonMove{{name: "baby-doll-eyes", moveType: "fairy"}}
+	levelUp.location = "eterna-forest"
+	options := eligibleEvolutions(chain, eevee, levelUp)
+	if names := evolutionSpecies(options); len(names) != 2 || names[0] != "sylveon" || names[1] != "leafeon" {
+		t.Fatalf("expected sylveon and leafeon to be options, got %v", names)
+	}
+
+	stone := eligibleEvolutions(chain, eevee, evolutionContext{trigger: evolutionTriggerItem, item: "water-stone", now: noon})
+	if len(stone) != 1 || stone[0].species != "vaporeon" || stone[0].item != "water-stone" {
+		t.Fatalf("expected the water stone to evolve into vaporeon, got %+v", stone)
+	}
+	if wrong := eligibleEvolutions(chain, eevee, evolutionContext{trigger: evolutionTriggerItem, item: "fire-stone"}); len(wrong) != 0 {
+		t.Fatalf("expected a fire stone to do nothing, got %v", evolutionSpecies(wrong))
+	}
+
+	c := &config{}
+	eevee.heldItems = []string{"kings-rock"}
+	trade := eligibleEvolutions(chain, eevee, itemEvolutionContext(c, tradeItem))
+	if len(trade) != 1 || trade[0].species != "politoed" {
+		t.Fatalf("expected the linking cord to trigger the trade evolution, got %v", evolutionSpecies(trade))
+	}
+}
+
+func TestIsTimeOfDay(t *testing.T) {
+	dusk := time.Date(2024, 1, 1, 17, 30, 0, 0, time.UTC)
+	if !isTimeOfDay(dusk, "day") || !isTimeOfDay(dusk, "dusk") || isTimeOfDay(dusk, "night") {
+		t.Fatalf("expected 17:30 to be day and dusk")
+	}
+	if !isTimeOfDay(time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC), "night") {
+		t.Fatalf("expected 2:00 to be night")
+	}
+}
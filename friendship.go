@@ -0,0 +1,74 @@
+package main
+
+const (
+	maxFriendship     = 255
+	defaultFriendship = 70
+)
+
+const (
+	friendshipLevelUp = "level-up"
+	friendshipBattle  = "battle"
+	friendshipWalk    = "walk"
+	friendshipFaint   = "faint"
+)
+
+// friendshipChanges lists how much each event changes friendship while it
+// is below 100, below 200 and at 200 or more; close Pokemon grow closer more
+// slowly.
+var friendshipChanges = map[string][3]int{
+	friendshipLevelUp: {5, 3, 2},
+	friendshipBattle:  {3, 2, 1},
+	friendshipWalk:    {2, 1, 1},
+	friendshipFaint:   {-1, -1, -1},
+}
+
+func changeFriendship(pokemon *Pokemon, event string) {
+	if pokemon == nil {
+		return
+	}
+	tier := 0
+	switch {
+	case pokemon.friendship >= 200:
+		tier = 2
+	case pokemon.friendship >= 100:
+		tier = 1
+	}
+	pokemon.friendship = max(0, min(maxFriendship, pokemon.friendship+friendshipChanges[event][tier]))
+}
+
+// friendshipMovePower is the power of Return and Frustration, which hit
+// harder the closer (or further) the Pokemon is to its trainer.
+func friendshipMovePower(pokemon Pokemon, move PokemonMove) (int, bool) {
+	switch move.name {
+	case "return":
+		return max(1, pokemon.friendship*10/25), true
+	case "frustration":
+		return max(1, (maxFriendship-pokemon.friendship)*10/25), true
+	}
+	return 0, false
+}
+
+func friendshipLabel(friendship int) string {
+	switch {
+	case friendship >= 250:
+		return "It's extremely friendly toward you."
+	case friendship >= 200:
+		return "It seems to be very happy."
+	case friendship >= 150:
+		return "It's quite friendly toward you."
+	case friendship >= 100:
+		return "It's getting used to you."
+	case friendship >= 50:
+		return "It's not used to you yet."
+	}
+	return "It doesn't seem to like you."
+}
+
+// walkParty counts a trip somewhere as a walk with every party member.
+func walkParty(c *config) {
+	for _, uid := range c.Party {
+		if selection, ok := findOwnedPokemon(c, uid); ok {
+			changeFriendship(&c.Pokedex[selection.key][selection.index], friendshipWalk)
+		}
+	}
+}
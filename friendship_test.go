@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChangeFriendshipByTier(t *testing.T) {
+	pokemon := Pokemon{friendship: 98}
+	changeFriendship(&pokemon, friendshipLevelUp)
+	if pokemon.friendship != 103 {
+		t.Fatalf("expected +5 below 100, got %d", pokemon.friendship)
+	}
+	changeFriendship(&pokemon, friendshipLevelUp)
+	if pokemon.friendship != 106 {
+		t.Fatalf("expected +3 from 100, got %d", pokemon.friendship)
+	}
+	pokemon.friendship = 254
+	changeFriendship(&pokemon, friendshipBattle)
+	changeFriendship(&pokemon, friendshipBattle)
+	if pokemon.friendship != maxFriendship {
+		t.Fatalf("expected friendship to cap at %d, got %d", maxFriendship, pokemon.friendship)
+	}
+	pokemon.friendship = 0
+	changeFriendship(&pokemon, friendshipFaint)
+	if pokemon.friendship != 0 {
+		t.Fatalf("expected friendship to stay at 0, got %d", pokemon.friendship)
+	}
+}
+
+func TestFriendshipMovesAndEvolution(t *testing.T) {
+	friendly := Pokemon{friendship: maxFriendship}
+	if power, ok := friendshipMovePower(friendly, PokemonMove{name: "return"}); !ok || power != 102 {
+		t.Fatalf("expected return to hit with 102 power, got %d", power)
+	}
+	if power, _ := friendshipMovePower(friendly, PokemonMove{name: "frustration"}); power != 1 {
+		t.Fatalf("expected frustration to be weak, got %d", power)
+	}
+
+	eevee := Pokemon{name: "eevee", species: "eevee", level: 20, friendship: 159}
+	ctx := evolutionContext{trigger: evolutionTriggerLevelUp, now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	if options := eligibleEvolutions(evolutionTestChain(t), eevee, ctx); len(options) != 0 {
+		t.Fatalf("expected espeon to need more friendship, got %v", evolutionSpecies(options))
+	}
+	eevee.friendship = 160
+	if options := eligibleEvolutions(evolutionTestChain(t), eevee, ctx); len(options) != 1 || options[0].species != "espeon" {
+		t.Fatalf("expected espeon at 160 friendship, got %v", evolutionSpecies(options))
+	}
+}
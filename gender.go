@@ -0,0 +1,91 @@
+package main
+
+import (
+	"math/rand"
+	"strings"
+)
+
+const (
+	genderMale       = "male"
+	genderFemale     = "female"
+	genderGenderless = "genderless"
+)
+
+// Evolution details number the genders the way the API does.
+const (
+	apiGenderFemale = 1
+	apiGenderMale   = 2
+)
+
+// rollGender picks a gender from the species gender rate, the chance of
+// being female in eighths, or -1 for genderless species.
+func rollGender(r *rand.Rand, genderRate int) string {
+	switch {
+	case genderRate < 0:
+		return genderGenderless
+	case r.Intn(8) < genderRate:
+		return genderFemale
+	}
+	return genderMale
+}
+
+func genderSymbol(gender string) string {
+	switch gender {
+	case genderMale:
+		return " ♂"
+	case genderFemale:
+		return " ♀"
+	}
+	return ""
+}
+
+func apiGenderName(apiGender int) string {
+	if apiGender == apiGenderFemale {
+		return genderFemale
+	}
+	return genderMale
+}
+
+func matchesAPIGender(gender string, apiGender int) bool {
+	switch apiGender {
+	case apiGenderFemale:
+		return gender == genderFemale
+	case apiGenderMale:
+		return gender == genderMale
+	}
+	return true
+}
+
+// rollForm picks one of the species' forms for a new individual.
+func rollForm(r *rand.Rand, forms []string) string {
+	if len(forms) == 0 {
+		return ""
+	}
+	return forms[r.Intn(len(forms))]
+}
+
+// carryForm keeps a form across evolution when the evolved species has the
+// matching form, e.g. burmy-sandy into wormadam-sandy.
+func carryForm(previousName, previousForm, nextName string, nextForms []string) string {
+	if len(nextForms) == 0 {
+		return ""
+	}
+	suffix := strings.TrimPrefix(previousForm, previousName)
+	if suffix != "" && suffix != previousForm {
+		for _, form := range nextForms {
+			if form == nextName+suffix {
+				return form
+			}
+		}
+	}
+	return nextForms[0]
+}
+
+// formLabel is the form's name without the species, or "" for the default
+// form.
+func formLabel(pokemon Pokemon) string {
+	if pokemon.form == "" || pokemon.form == pokemon.name {
+		return ""
+	}
+	return strings.TrimPrefix(strings.TrimPrefix(pokemon.form, pokemon.name), "-")
+}
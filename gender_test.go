@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	"github.com/dey12956/pokedexcli/internal/pokeapi"
+)
+
+func TestRollGenderFollowsRate(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	if rollGender(r, -1) != genderGenderless || rollGender(r, 0) != genderMale || rollGender(r, 8) != genderFemale {
+		t.Fatalf("expected fixed genders for rates -1, 0 and 8")
+	}
+	female := 0
+	for range 8000 {
+		if rollGender(r, 1) == genderFemale {
+			female++
+		}
+	}
+	if female < 800 || female > 1200 {
+		t.Fatalf("expected about 1 in 8 to be female, got %d of 8000", female)
+	}
+}
+
+func TestFormsAndGenderInLabelsAndEvolution(t *testing.T) {
+	burmy := Pokemon{name: "burmy", species: "burmy", level: 20, gender: genderFemale, form: "burmy-sandy"}
+	if label := pokemonLabel(burmy); label != "burmy [sandy] ♀ (Lv 20)" {
+		t.Fatalf("unexpected label %q", label)
+	}
+	if form := carryForm("burmy", "burmy-sandy", "wormadam", []string{"wormadam-plant", "wormadam-sandy"}); form != "wormadam-sandy" {
+		t.Fatalf("expected the sandy form to carry over, got %q", form)
+	}
+	if form := carryForm("pikachu", "pikachu", "raichu", []string{"raichu"}); form != "raichu" {
+		t.Fatalf("expected the default form, got %q", form)
+	}
+
+	var chain pokeapi.EvolutionChainLink
+	payload := `{"species":{"name":"burmy"},"evolves_to":[
+		{"species":{"name":"wormadam"},"evolution_details":[{"trigger":{"name":"level-up"},"min_level":20,"gender":1}]},
+		{"species":{"name":"mothim"},"evolution_details":[{"trigger":{"name":"level-up"},"min_level":20,"gender":2}]}
+	]}`
+	if err := json.Unmarshal([]byte(payload), &chain); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := evolutionContext{trigger: evolutionTriggerLevelUp}
+	if options := eligibleEvolutions(chain, burmy, ctx); len(options) != 1 || options[0].species != "wormadam" {
+		t.Fatalf("expected a female burmy to become wormadam, got %v", evolutionSpecies(options))
+	}
+	burmy.gender = genderMale
+	if options := eligibleEvolutions(chain, burmy, ctx); len(options) != 1 || options[0].species != "mothim" {
+		t.Fatalf("expected a male burmy to become mothim, got %v", evolutionSpecies(options))
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import "fmt"
+
+func restoreHP(pokemon *Pokemon) {
+	if pokemon == nil {
+		return
+	}
+	pokemon.currentHP = maxHP(*pokemon)
+	pokemon.fainted = false
+}
+
+// adjustHPForMaxChange keeps the damage a Pokemon has taken when its maximum
+// HP changes, e.g. after leveling up or evolving.
+func adjustHPForMaxChange(pokemon *Pokemon, prevMax int) {
+	if pokemon == nil || pokemon.fainted {
+		return
+	}
+	nextMax := maxHP(*pokemon)
+	pokemon.currentHP = min(nextMax, max(1, pokemon.currentHP+nextMax-prevMax))
+}
+
+func healBattlePokemon(target *battlePokemon, amount int) int {
+	if target == nil || target.current <= 0 || amount <= 0 {
+		return 0
+	}
+	healed := min(amount, target.max-target.current)
+	target.current += healed
+	return healed
+}
+
+func reviveBattlePokemon(target *battlePokemon, item itemDefinition) {
+	target.current = target.max
+	if item.reviveHalf {
+		target.current = max(1, target.max/2)
+	}
+	target.status = statusNone
+}
+
+// commitHP writes the battle's HP and status back to the Pokemon. Fainting
+// clears a status.
+func (p *battlePokemon) commitHP() {
+	p.pokemon.currentHP = p.current
+	p.pokemon.fainted = p.current <= 0
+	p.pokemon.status = ""
+	if !p.pokemon.fainted && p.status != statusNone {
+		p.pokemon.status = p.status
+	}
+}
+
+func (p *battlePokemon) refreshHP() {
+	p.max = maxHP(p.pokemon)
+	p.current = min(p.max, max(0, p.pokemon.currentHP))
+	if p.pokemon.fainted {
+		p.current = 0
+	}
+	p.status = statusNone
+	if p.pokemon.status != "" {
+		p.status = p.pokemon.status
+	}
+}
+
+func hpLabel(pokemon Pokemon) string {
+	if pokemon.fainted {
+		return "fainted"
+	}
+	if pokemon.status != "" {
+		return fmt.Sprintf("%s, %s", formatHP(pokemon.currentHP, maxHP(pokemon)), pokemon.status)
+	}
+	return formatHP(pokemon.currentHP, maxHP(pokemon))
+}
+
+func formatHP(current, max int) string {
+	return fmt.Sprintf("%d/%d HP", current, max)
+}
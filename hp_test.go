@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecordToPokemonDefaultsLegacyHPToFull(t *testing.T) {
+	poke := recordToPokemon(pokemonRecord{Name: "pidgey", Level: 5, Stats: map[string]int{"hp": 40}})
+	if poke.currentHP != 50 || poke.fainted {
+		t.Fatalf("expected full HP 50, got %d (fainted=%t)", poke.currentHP, poke.fainted)
+	}
+	fainted := recordToPokemon(pokemonRecord{Name: "pidgey", Level: 5, Stats: map[string]int{"hp": 40}, Fainted: true})
+	if fainted.currentHP != 0 || !fainted.fainted {
+		t.Fatalf("expected fainted Pokemon to stay at 0 HP, got %d", fainted.currentHP)
+	}
+}
+
+func TestAdjustHPForMaxChangeKeepsDamage(t *testing.T) {
+	poke := Pokemon{level: 5, stats: map[string]int{"hp": 40}, currentHP: 30}
+	prevMax := maxHP(poke)
+	poke.level = 6
+	adjustHPForMaxChange(&poke, prevMax)
+	if poke.currentHP != 32 {
+		t.Fatalf("expected HP to rise with max HP to 32, got %d", poke.currentHP)
+	}
+}
+
+func TestHealCooldownRemaining(t *testing.T) {
+	now := time.Now()
+	c := &config{HealCooldown: 10 * time.Minute, LastHealAt: now.Add(-4 * time.Minute)}
+	if got := healCooldownRemaining(c, now); got != 6*time.Minute {
+		t.Fatalf("expected 6m remaining, got %s", got)
+	}
+	c.HealCooldown = 0
+	if got := healCooldownRemaining(c, now); got != 0 {
+		t.Fatalf("expected no cooldown when disabled, got %s", got)
+	}
+}
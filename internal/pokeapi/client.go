@@ -19,3 +19,11 @@ func NewClient(timeout time.Duration, cacheInterval time.Duration) Client {
 		cache: pokecache.NewCache(cacheInterval, cacheInterval),
 	}
 }
+
+// NewClientWithTransport is NewClient with the requests sent through
+// transport, e.g. to serve canned responses in tests.
+func NewClientWithTransport(transport http.RoundTripper, timeout time.Duration, cacheInterval time.Duration) Client {
+	client := NewClient(timeout, cacheInterval)
+	client.httpClient.Transport = transport
+	return client
+}
@@ -1,5 +1,7 @@
 package pokeapi
 
+import "net/url"
+
 func (c *Client) GetMove(resourceURL string) (MoveResponse, error) {
 	resp := MoveResponse{}
 	if err := c.getResource(resourceURL, &resp); err != nil {
@@ -7,3 +9,7 @@ func (c *Client) GetMove(resourceURL string) (MoveResponse, error) {
 	}
 	return resp, nil
 }
+
+func (c *Client) GetMoveByName(name string) (MoveResponse, error) {
+	return c.GetMove(baseURL + "/move/" + url.PathEscape(name))
+}
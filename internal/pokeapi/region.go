@@ -0,0 +1,29 @@
+package pokeapi
+
+import "net/url"
+
+func (c *Client) ListRegions() (Response, error) {
+	resp := Response{}
+	if err := c.getResource(baseURL+"/region", &resp); err != nil {
+		return Response{}, err
+	}
+	return resp, nil
+}
+
+func (c *Client) GetRegion(name string) (RegionResponse, error) {
+	resourceURL := baseURL + "/region/" + url.PathEscape(name)
+	resp := RegionResponse{}
+	if err := c.getResource(resourceURL, &resp); err != nil {
+		return RegionResponse{}, err
+	}
+	return resp, nil
+}
+
+func (c *Client) GetLocation(name string) (LocationResponse, error) {
+	resourceURL := baseURL + "/location/" + url.PathEscape(name)
+	resp := LocationResponse{}
+	if err := c.getResource(resourceURL, &resp); err != nil {
+		return LocationResponse{}, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,39 @@
+package pokeapi
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/dey12956/pokedexcli/internal/pokecache"
+)
+
+func TestRegionAndLocationDecodeFromCache(t *testing.T) {
+	cache := pokecache.NewCache(time.Second, time.Second)
+	t.Cleanup(cache.Close)
+	cache.Add(baseURL+"/region/kanto", []byte(`{"name":"kanto","locations":[{"name":"viridian-forest","url":"u"}]}`))
+	cache.Add(baseURL+"/location/viridian-forest", []byte(`{"name":"viridian-forest","region":{"name":"kanto"},"areas":[{"name":"viridian-forest-area","url":"u"}]}`))
+
+	client := Client{
+		httpClient: http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, fmt.Errorf("unexpected http request: %s", req.URL.String())
+		})},
+		cache: cache,
+	}
+
+	region, err := client.GetRegion("kanto")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(region.Locations) != 1 || region.Locations[0].Name != "viridian-forest" {
+		t.Fatalf("unexpected locations %+v", region.Locations)
+	}
+	location, err := client.GetLocation(region.Locations[0].Name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if location.Region == nil || location.Region.Name != "kanto" || len(location.Areas) != 1 || location.Areas[0].Name != "viridian-forest-area" {
+		t.Fatalf("unexpected location %+v", location)
+	}
+}
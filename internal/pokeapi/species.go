@@ -11,6 +11,15 @@ func (c *Client) GetPokemonSpecies(name string) (PokemonSpeciesResponse, error)
 	return resp, nil
 }
 
+func (c *Client) GetPokemonForm(name string) (PokemonFormResponse, error) {
+	resourceURL := baseURL + "/pokemon-form/" + url.PathEscape(name)
+	resp := PokemonFormResponse{}
+	if err := c.getResource(resourceURL, &resp); err != nil {
+		return PokemonFormResponse{}, err
+	}
+	return resp, nil
+}
+
 func (c *Client) GetGrowthRate(resourceURL string) (GrowthRateResponse, error) {
 	resp := GrowthRateResponse{}
 	if err := c.getResource(resourceURL, &resp); err != nil {
@@ -4,8 +4,27 @@ type MoveResponse struct {
 	Name     string `json:"name"`
 	Power    *int   `json:"power"`
 	Accuracy *int   `json:"accuracy"`
+	PP       *int   `json:"pp"`
 	Priority int    `json:"priority"`
 	Type     struct {
 		Name string `json:"name"`
 	} `json:"type"`
+	DamageClass struct {
+		Name string `json:"name"`
+	} `json:"damage_class"`
+	Target struct {
+		Name string `json:"name"`
+	} `json:"target"`
+	StatChanges []struct {
+		Change int `json:"change"`
+		Stat   struct {
+			Name string `json:"name"`
+		} `json:"stat"`
+	} `json:"stat_changes"`
+	Meta *struct {
+		StatChance int `json:"stat_chance"`
+		Category   struct {
+			Name string `json:"name"`
+		} `json:"category"`
+	} `json:"meta"`
 }
@@ -0,0 +1,12 @@
+package pokeapi
+
+type RegionResponse struct {
+	Name      string             `json:"name"`
+	Locations []NamedAPIResource `json:"locations"`
+}
+
+type LocationResponse struct {
+	Name   string             `json:"name"`
+	Region *NamedAPIResource  `json:"region"`
+	Areas  []NamedAPIResource `json:"areas"`
+}
@@ -6,6 +6,8 @@ type NamedAPIResource struct {
 }
 
 type PokemonSpeciesResponse struct {
+	BaseHappiness  *int             `json:"base_happiness"`
+	GenderRate     int              `json:"gender_rate"`
 	GrowthRate     NamedAPIResource `json:"growth_rate"`
 	EvolutionChain struct {
 		URL string `json:"url"`
@@ -30,5 +32,25 @@ type EvolutionChainLink struct {
 }
 
 type EvolutionDetail struct {
-	MinLevel *int `json:"min_level"`
+	MinLevel      *int              `json:"min_level"`
+	Item          *NamedAPIResource `json:"item"`
+	Trigger       NamedAPIResource  `json:"trigger"`
+	MinHappiness  *int              `json:"min_happiness"`
+	TimeOfDay     string            `json:"time_of_day"`
+	KnownMove     *NamedAPIResource `json:"known_move"`
+	KnownMoveType *NamedAPIResource `json:"known_move_type"`
+	HeldItem      *NamedAPIResource `json:"held_item"`
+	Location      *NamedAPIResource `json:"location"`
+	Gender        *int              `json:"gender"`
+}
+
+type PokemonFormResponse struct {
+	Name     string `json:"name"`
+	FormName string `json:"form_name"`
+	Sprites  struct {
+		FrontDefault     string `json:"front_default"`
+		FrontFemale      string `json:"front_female"`
+		FrontShiny       string `json:"front_shiny"`
+		FrontShinyFemale string `json:"front_shiny_female"`
+	} `json:"sprites"`
 }
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	itemCategoryBall       = "pokeballs"
+	itemCategoryHealing    = "healing"
+	itemCategoryStatusCure = "status-cures"
+	itemCategoryRevival    = "revival"
+	itemCategoryStatBoost  = "stat-boosts"
+	itemCategoryInflict    = "status-inflicting"
+	itemCategoryEvolution  = "evolution"
+	itemCategoryKey        = "key-items"
+	itemCategoryOther      = "other"
+)
+
+var itemCategoryOrder = []string{
+	itemCategoryBall,
+	itemCategoryHealing,
+	itemCategoryStatusCure,
+	itemCategoryRevival,
+	itemCategoryStatBoost,
+	itemCategoryInflict,
+	itemCategoryEvolution,
+	itemCategoryKey,
+	itemCategoryOther,
+}
+
+const (
+	statusBurn   = "burn"
+	statusPoison = "poison"
+	statusFreeze = "freeze"
+)
+
+// itemDefinition describes what an item does. Items are keyed by their
+// PokeAPI item name; unknown names still live in the bag as "other" items.
+type itemDefinition struct {
+	name       string
+	category   string
+	ballFactor float64
+	guaranteed bool
+	heal       int
+	fullHeal   bool
+	cures      []string
+	reviveHalf bool
+	stat       string
+	// inflicts is the status a held item gives its holder at the end of
+	// each turn.
+	inflicts string
+}
+
+var itemCatalog = map[string]itemDefinition{
+	"poke-ball":     {name: "poke-ball", category: itemCategoryBall, ballFactor: 0.7},
+	"great-ball":    {name: "great-ball", category: itemCategoryBall, ballFactor: 1.0},
+	"ultra-ball":    {name: "ultra-ball", category: itemCategoryBall, ballFactor: 1.15},
+	"master-ball":   {name: "master-ball", category: itemCategoryBall, guaranteed: true},
+	"potion":        {name: "potion", category: itemCategoryHealing, heal: 20},
+	"super-potion":  {name: "super-potion", category: itemCategoryHealing, heal: 60},
+	"hyper-potion":  {name: "hyper-potion", category: itemCategoryHealing, heal: 120},
+	"max-potion":    {name: "max-potion", category: itemCategoryHealing, fullHeal: true},
+	"full-restore":  {name: "full-restore", category: itemCategoryHealing, fullHeal: true, cures: []string{statusSleep, statusParalysis, statusBurn, statusPoison, statusFreeze}},
+	"antidote":      {name: "antidote", category: itemCategoryStatusCure, cures: []string{statusPoison}},
+	"paralyze-heal": {name: "paralyze-heal", category: itemCategoryStatusCure, cures: []string{statusParalysis}},
+	"awakening":     {name: "awakening", category: itemCategoryStatusCure, cures: []string{statusSleep}},
+	"burn-heal":     {name: "burn-heal", category: itemCategoryStatusCure, cures: []string{statusBurn}},
+	"ice-heal":      {name: "ice-heal", category: itemCategoryStatusCure, cures: []string{statusFreeze}},
+	"full-heal":     {name: "full-heal", category: itemCategoryStatusCure, cures: []string{statusSleep, statusParalysis, statusBurn, statusPoison, statusFreeze}},
+	"revive":        {name: "revive", category: itemCategoryRevival, reviveHalf: true},
+	"max-revive":    {name: "max-revive", category: itemCategoryRevival},
+	"x-attack":      {name: "x-attack", category: itemCategoryStatBoost, stat: "attack"},
+	"x-defense":     {name: "x-defense", category: itemCategoryStatBoost, stat: "defense"},
+	"x-sp-atk":      {name: "x-sp-atk", category: itemCategoryStatBoost, stat: "special-attack"},
+	"x-sp-def":      {name: "x-sp-def", category: itemCategoryStatBoost, stat: "special-defense"},
+	"x-speed":       {name: "x-speed", category: itemCategoryStatBoost, stat: "speed"},
+	"x-accuracy":    {name: "x-accuracy", category: itemCategoryStatBoost, stat: "accuracy"},
+	"flame-orb":     {name: "flame-orb", category: itemCategoryInflict, inflicts: statusBurn},
+	"toxic-orb":     {name: "toxic-orb", category: itemCategoryInflict, inflicts: statusPoison},
+	"fire-stone":    {name: "fire-stone", category: itemCategoryEvolution},
+	"water-stone":   {name: "water-stone", category: itemCategoryEvolution},
+	"thunder-stone": {name: "thunder-stone", category: itemCategoryEvolution},
+	"leaf-stone":    {name: "leaf-stone", category: itemCategoryEvolution},
+	"moon-stone":    {name: "moon-stone", category: itemCategoryEvolution},
+	"sun-stone":     {name: "sun-stone", category: itemCategoryEvolution},
+	"shiny-stone":   {name: "shiny-stone", category: itemCategoryEvolution},
+	"dusk-stone":    {name: "dusk-stone", category: itemCategoryEvolution},
+	"dawn-stone":    {name: "dawn-stone", category: itemCategoryEvolution},
+	"ice-stone":     {name: "ice-stone", category: itemCategoryEvolution},
+	"linking-cord":  {name: "linking-cord", category: itemCategoryEvolution},
+	"old-rod":       {name: "old-rod", category: itemCategoryKey},
+	"good-rod":      {name: "good-rod", category: itemCategoryKey},
+	"super-rod":     {name: "super-rod", category: itemCategoryKey},
+}
+
+var ballOrder = []string{"poke-ball", "great-ball", "ultra-ball", "master-ball"}
+
+func lookupItem(name string) itemDefinition {
+	if item, exists := itemCatalog[name]; exists {
+		return item
+	}
+	return itemDefinition{name: name, category: itemCategoryOther}
+}
+
+func itemDisplayName(name string) string {
+	words := strings.Split(name, "-")
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// Bag holds item quantities keyed by PokeAPI item name.
+type Bag map[string]int
+
+func (b Bag) count(item string) int {
+	return b[item]
+}
+
+func (b Bag) add(item string, quantity int) {
+	if quantity <= 0 {
+		return
+	}
+	b[item] += quantity
+}
+
+func (b Bag) take(item string) bool {
+	if b[item] <= 0 {
+		return false
+	}
+	b[item]--
+	if b[item] == 0 {
+		delete(b, item)
+	}
+	return true
+}
+
+// itemsIn lists the items held in the given categories, in catalog order.
+func (b Bag) itemsIn(categories ...string) []string {
+	items := make([]string, 0)
+	for name, quantity := range b {
+		if quantity <= 0 {
+			continue
+		}
+		category := lookupItem(name).category
+		for _, wanted := range categories {
+			if category == wanted {
+				items = append(items, name)
+				break
+			}
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		ci, cj := categoryRank(lookupItem(items[i]).category), categoryRank(lookupItem(items[j]).category)
+		if ci != cj {
+			return ci < cj
+		}
+		return items[i] < items[j]
+	})
+	return items
+}
+
+func categoryRank(category string) int {
+	for i, c := range itemCategoryOrder {
+		if c == category {
+			return i
+		}
+	}
+	return len(itemCategoryOrder)
+}
+
+func defaultBag() Bag {
+	return Bag{
+		"poke-ball":  10,
+		"great-ball": 5,
+		"ultra-ball": 2,
+		"potion":     3,
+	}
+}
+
+func (item itemDefinition) curesStatus(status string) bool {
+	for _, cure := range item.cures {
+		if cure == status {
+			return true
+		}
+	}
+	return false
+}
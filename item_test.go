@@ -0,0 +1,122 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestBagFromInventoryRecordMigratesCounters(t *testing.T) {
+	bag := bagFromInventoryRecord(inventoryRecord{Pokeball: 12, GreatBall: 0, UltraBall: 3, Potion: 4})
+	if bag.count("poke-ball") != 12 || bag.count("ultra-ball") != 3 || bag.count("potion") != 4 {
+		t.Fatalf("unexpected migrated bag: %v", bag)
+	}
+	if _, exists := bag["great-ball"]; exists {
+		t.Fatalf("expected empty counters to be skipped, got %v", bag)
+	}
+}
+
+func TestBagTakeRemovesEmptyEntries(t *testing.T) {
+	bag := Bag{"potion": 1}
+	if !bag.take("potion") {
+		t.Fatalf("expected to take a potion")
+	}
+	if bag.take("potion") {
+		t.Fatalf("expected no potions left")
+	}
+	if _, exists := bag["potion"]; exists {
+		t.Fatalf("expected empty entry to be removed")
+	}
+}
+
+func TestBagItemsInOrdersByCategory(t *testing.T) {
+	bag := Bag{"revive": 1, "potion": 2, "ultra-ball": 1, "poke-ball": 3, "mystery-thing": 1}
+	got := bag.itemsIn(itemCategoryOrder...)
+	want := []string{"poke-ball", "ultra-ball", "potion", "revive", "mystery-thing"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestUseItemOnPokemonHealsAndRevives(t *testing.T) {
+	poke := Pokemon{name: "pidgey", level: 5, stats: map[string]int{"hp": 40}, currentHP: 10}
+	if _, err := useItemOnPokemon(nil, lookupItem("potion"), &poke); err != nil {
+		t.Fatalf("potion: %v", err)
+	}
+	if poke.currentHP != 30 {
+		t.Fatalf("expected 30 HP after potion, got %d", poke.currentHP)
+	}
+	if _, err := useItemOnPokemon(nil, lookupItem("revive"), &poke); err != errNoEffect {
+		t.Fatalf("expected revive to have no effect on a healthy Pokemon, got %v", err)
+	}
+	poke.fainted, poke.currentHP = true, 0
+	if _, err := useItemOnPokemon(nil, lookupItem("revive"), &poke); err != nil {
+		t.Fatalf("revive: %v", err)
+	}
+	if poke.fainted || poke.currentHP != 25 {
+		t.Fatalf("expected revive to half HP, got %d (fainted=%t)", poke.currentHP, poke.fainted)
+	}
+}
+
+func TestStatusCuresClearAPersistentStatus(t *testing.T) {
+	poke := Pokemon{name: "pidgey", level: 5, stats: map[string]int{"hp": 40}, currentHP: 50, status: statusPoison}
+	if _, err := useItemOnPokemon(nil, lookupItem("burn-heal"), &poke); err != errNoEffect {
+		t.Fatalf("expected burn-heal to have no effect on poison, got %v", err)
+	}
+	if _, err := useItemOnPokemon(nil, lookupItem("antidote"), &poke); err != nil {
+		t.Fatalf("antidote: %v", err)
+	}
+	if poke.status != "" {
+		t.Fatalf("expected antidote to cure poison, got %q", poke.status)
+	}
+	if _, err := useItemOnPokemon(nil, lookupItem("antidote"), &poke); err != errNoEffect {
+		t.Fatalf("expected antidote to have no effect on a healthy Pokemon, got %v", err)
+	}
+
+	poke.status = statusBurn
+	if _, err := useItemOnPokemon(nil, lookupItem("full-restore"), &poke); err != nil {
+		t.Fatalf("full-restore: %v", err)
+	}
+	if poke.status != "" {
+		t.Fatalf("expected full-restore to cure a burn at full HP, got %q", poke.status)
+	}
+}
+
+func TestHeldOrbStatusLastsAfterBattle(t *testing.T) {
+	c := &config{Bag: Bag{"toxic-orb": 1, "flame-orb": 1}}
+	pidgey := Pokemon{name: "pidgey", level: 10, types: []string{"normal"}, stats: map[string]int{"hp": 40}}
+	restoreHP(&pidgey)
+	if _, err := useItemOnPokemon(c, lookupItem("toxic-orb"), &pidgey); err != nil {
+		t.Fatalf("toxic-orb: %v", err)
+	}
+	if _, err := useItemOnPokemon(c, lookupItem("toxic-orb"), &pidgey); err != errNoEffect {
+		t.Fatalf("expected giving the same orb twice to have no effect, got %v", err)
+	}
+	c.Bag.take("toxic-orb")
+	if _, err := useItemOnPokemon(c, lookupItem("flame-orb"), &pidgey); err != nil {
+		t.Fatalf("flame-orb: %v", err)
+	}
+	if pidgey.heldItem != "flame-orb" || c.Bag.count("toxic-orb") != 1 {
+		t.Fatalf("expected the toxic orb back in the bag, holding %q with %v", pidgey.heldItem, c.Bag)
+	}
+
+	holder := newBattlePokemon(pidgey)
+	events := endOfTurn(rand.New(rand.NewSource(1)), &holder)
+	if holder.status != statusBurn || len(events) != 1 || events[0].Kind != eventStatus {
+		t.Fatalf("expected the flame orb to burn its holder, got %q and %+v", holder.status, events)
+	}
+	holder.commitHP()
+	record := pokemonToRecord(holder.pokemon)
+	restored := recordToPokemon(record)
+	if restored.status != statusBurn || restored.heldItem != "flame-orb" {
+		t.Fatalf("expected the burn and orb to be saved, got %q holding %q", restored.status, restored.heldItem)
+	}
+	healPokemon(&restored)
+	if restored.status != "" {
+		t.Fatalf("expected the Pokemon Center to cure the status")
+	}
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dey12956/pokedexcli/internal/pokeapi"
+)
+
+const maxKnownMoves = 4
+
+// learnsetEntry is a move a Pokemon learns by leveling up.
+type learnsetEntry struct {
+	name  string
+	url   string
+	level int
+}
+
+// levelUpLearnset lists the level-up moves for versionGroup in the order
+// they are learned. Pokemon that didn't exist in that version group use the
+// newest version group they appear in instead.
+func levelUpLearnset(resp pokeapi.CatchPokemonResponse, versionGroup string) []learnsetEntry {
+	entries := learnsetFor(resp, versionGroup)
+	if len(entries) == 0 {
+		entries = learnsetFor(resp, newestVersionGroup(resp))
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].level < entries[j].level
+	})
+	return entries
+}
+
+func learnsetFor(resp pokeapi.CatchPokemonResponse, versionGroup string) []learnsetEntry {
+	entries := make([]learnsetEntry, 0)
+	for _, move := range resp.Moves {
+		for _, detail := range move.VersionGroupDetails {
+			if detail.VersionGroup.Name == versionGroup && detail.MoveLearnMethod.Name == "level-up" {
+				entries = append(entries, learnsetEntry{name: move.Move.Name, url: move.Move.URL, level: detail.LevelLearnedAt})
+				break
+			}
+		}
+	}
+	return entries
+}
+
+// newestVersionGroup picks the level-up version group with the highest
+// resource id, which is the most recent game.
+func newestVersionGroup(resp pokeapi.CatchPokemonResponse) string {
+	newest, newestID := "", -1
+	for _, move := range resp.Moves {
+		for _, detail := range move.VersionGroupDetails {
+			if detail.MoveLearnMethod.Name != "level-up" {
+				continue
+			}
+			if id := resourceID(detail.VersionGroup.URL); id > newestID {
+				newest, newestID = detail.VersionGroup.Name, id
+			}
+		}
+	}
+	return newest
+}
+
+func resourceID(url string) int {
+	parts := strings.Split(strings.TrimSuffix(url, "/"), "/")
+	id, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// latestMoves is what a Pokemon at level knows: its last four level-up
+// moves, without repeats.
+func latestMoves(learnset []learnsetEntry, level int) []learnsetEntry {
+	known := make([]learnsetEntry, 0, maxKnownMoves)
+	for i := len(learnset) - 1; i >= 0 && len(known) < maxKnownMoves; i-- {
+		entry := learnset[i]
+		if entry.level > level || containsMove(known, entry.name) {
+			continue
+		}
+		known = append(known, entry)
+	}
+	for i, j := 0, len(known)-1; i < j; i, j = i+1, j-1 {
+		known[i], known[j] = known[j], known[i]
+	}
+	return known
+}
+
+// movesLearnedBetween lists the moves learned after level from up to and
+// including level to.
+func movesLearnedBetween(learnset []learnsetEntry, from, to int) []learnsetEntry {
+	learned := make([]learnsetEntry, 0)
+	for _, entry := range learnset {
+		if entry.level > from && entry.level <= to {
+			learned = append(learned, entry)
+		}
+	}
+	return learned
+}
+
+func containsMove(entries []learnsetEntry, name string) bool {
+	for _, entry := range entries {
+		if entry.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func fetchMoves(c *config, entries []learnsetEntry) ([]PokemonMove, error) {
+	moves := make([]PokemonMove, 0, len(entries))
+	for _, entry := range entries {
+		moveResp, err := c.pokeapiClient.GetMove(entry.url)
+		if err != nil {
+			return nil, err
+		}
+		moves = append(moves, buildMove(moveResp))
+	}
+	return moves, nil
+}
+
+// resetMoves replaces a Pokemon's moves with what it would know at its
+// current level, e.g. after its level was set directly.
+func resetMoves(c *config, pokemon *Pokemon) error {
+	resp, err := c.pokeapiClient.GetPokemon(pokemon.name)
+	if err != nil {
+		return err
+	}
+	moves, err := fetchMoves(c, latestMoves(levelUpLearnset(resp, versionGroup(c)), pokemon.level))
+	if err != nil {
+		return err
+	}
+	pokemon.moves = moves
+	return nil
+}
+
+// learnLevelUpMoves teaches the moves a Pokemon picks up between two
+// levels.
+func learnLevelUpMoves(c *config, pokemon *Pokemon, fromLevel int) error {
+	resp, err := c.pokeapiClient.GetPokemon(pokemon.name)
+	if err != nil {
+		return err
+	}
+	entries := movesLearnedBetween(levelUpLearnset(resp, versionGroup(c)), fromLevel, pokemon.level)
+	for _, entry := range entries {
+		if knowsMove(*pokemon, entry.name) {
+			continue
+		}
+		moves, err := fetchMoves(c, []learnsetEntry{entry})
+		if err != nil {
+			return err
+		}
+		if err := learnMove(c, pokemon, moves[0]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func knowsMove(pokemon Pokemon, name string) bool {
+	for _, move := range pokemon.moves {
+		if move.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// learnMove adds move, asking which move to forget when four are already
+// known.
+func learnMove(c *config, pokemon *Pokemon, move PokemonMove) error {
+	if len(pokemon.moves) < maxKnownMoves {
+		pokemon.moves = append(pokemon.moves, move)
+		fmt.Printf("%s learned %s!\n", pokemon.name, move.name)
+		return nil
+	}
+	choose := c.forgetMove
+	if choose == nil {
+		choose = promptForgetMove
+	}
+	forget, err := choose(*pokemon, move)
+	if err != nil {
+		return err
+	}
+	if forget < 0 || forget >= len(pokemon.moves) {
+		fmt.Printf("%s did not learn %s.\n", pokemon.name, move.name)
+		return nil
+	}
+	fmt.Printf("%s forgot %s and learned %s!\n", pokemon.name, pokemon.moves[forget].name, move.name)
+	pokemon.moves[forget] = move
+	return nil
+}
+
+// promptForgetMove asks the player which move to forget; -1 keeps the
+// current moves.
+func promptForgetMove(pokemon Pokemon, move PokemonMove) (int, error) {
+	fmt.Printf("\n%s wants to learn %s, but already knows %d moves.\n", pokemon.name, move.name, maxKnownMoves)
+	fmt.Println("Forget which move?")
+	for i, known := range pokemon.moves {
+		fmt.Printf("%d) %s (PP %s, power %d, type %s)\n", i+1, known.name, formatPP(known), known.power, known.moveType)
+	}
+	fmt.Printf("%d) Don't learn %s (power %d, type %s)\n", len(pokemon.moves)+1, move.name, move.power, move.moveType)
+	choice, cancelled, err := promptChoice(bufio.NewReader(os.Stdin), "Forget > ", len(pokemon.moves)+1)
+	if err != nil {
+		return -1, err
+	}
+	if cancelled || choice > len(pokemon.moves) {
+		return -1, nil
+	}
+	return choice - 1, nil
+}
+
+// trimMoves drops the extra moves older saves stored, keeping the four
+// that battles already used.
+func trimMoves(pokemon *Pokemon) {
+	if len(pokemon.moves) > maxKnownMoves {
+		pokemon.moves = pokemon.moves[:maxKnownMoves]
+	}
+}
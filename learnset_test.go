@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dey12956/pokedexcli/internal/pokeapi"
+)
+
+func learnsetTestResponse(t *testing.T) pokeapi.CatchPokemonResponse {
+	t.Helper()
+	move := func(name string, details ...string) string {
+		return `{"move":{"name":"` + name + `","url":"https://pokeapi.co/api/v2/move/` + name + `/"},"version_group_details":[` + strings.Join(details, ",") + `]}`
+	}
+	levelUp := func(level, group, id string) string {
+		return `{"level_learned_at":` + level + `,"version_group":{"name":"` + group + `","url":"https://pokeapi.co/api/v2/version-group/` + id + `/"},"move_learn_method":{"name":"level-up"}}`
+	}
+	machine := `{"level_learned_at":0,"version_group":{"name":"red-blue","url":"https://pokeapi.co/api/v2/version-group/1/"},"move_learn_method":{"name":"machine"}}`
+	payload := `{"name":"pikachu","moves":[` +
+		move("thunder-shock", levelUp("1", "red-blue", "1")) + `,` +
+		move("growl", levelUp("1", "red-blue", "1")) + `,` +
+		move("thunder-wave", levelUp("9", "red-blue", "1")) + `,` +
+		move("quick-attack", levelUp("16", "red-blue", "1")) + `,` +
+		move("swift", levelUp("26", "red-blue", "1")) + `,` +
+		move("thunderbolt", machine) + `,` +
+		move("nuzzle", levelUp("1", "sword-shield", "20")) + `]}`
+	var resp pokeapi.CatchPokemonResponse
+	if err := json.Unmarshal([]byte(payload), &resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return resp
+}
+
+func TestLatestMovesUsesLevelUpLearnset(t *testing.T) {
+	learnset := levelUpLearnset(learnsetTestResponse(t), versionGroup(nil))
+	if len(learnset) != 5 {
+		t.Fatalf("expected 5 level-up moves, got %+v", learnset)
+	}
+	known := latestMoves(learnset, 20)
+	names := make([]string, 0, len(known))
+	for _, entry := range known {
+		names = append(names, entry.name)
+	}
+	if len(names) != 4 || names[0] != "thunder-shock" || names[3] != "quick-attack" {
+		t.Fatalf("expected the four moves known at level 20, got %v", names)
+	}
+	if learned := movesLearnedBetween(learnset, 20, 26); len(learned) != 1 || learned[0].name != "swift" {
+		t.Fatalf("expected swift at level 26, got %+v", learned)
+	}
+
+	fallback := levelUpLearnset(learnsetTestResponse(t), "gold-silver")
+	if len(fallback) != 1 || fallback[0].name != "nuzzle" {
+		t.Fatalf("expected the newest version group as a fallback, got %+v", fallback)
+	}
+}
+
+func TestLearnMoveForgetsChosenMove(t *testing.T) {
+	c := &config{forgetMove: func(pokemon Pokemon, move PokemonMove) (int, error) { return 1, nil }}
+	pokemon := Pokemon{name: "pikachu", moves: []PokemonMove{{name: "a"}, {name: "b"}, {name: "c"}}}
+	if err := learnMove(c, &pokemon, PokemonMove{name: "d"}); err != nil || len(pokemon.moves) != 4 {
+		t.Fatalf("expected a free slot to be used, got %+v (%v)", pokemon.moves, err)
+	}
+	if err := learnMove(c, &pokemon, PokemonMove{name: "swift"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pokemon.moves[1].name != "swift" || len(pokemon.moves) != 4 {
+		t.Fatalf("expected swift to replace b, got %+v", pokemon.moves)
+	}
+
+	c.forgetMove = func(pokemon Pokemon, move PokemonMove) (int, error) { return -1, nil }
+	learnMove(c, &pokemon, PokemonMove{name: "thunder"})
+	if knowsMove(pokemon, "thunder") {
+		t.Fatalf("expected the move to be skipped")
+	}
+}
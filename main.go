@@ -24,7 +24,7 @@ func main() {
 	c := &config{
 		pokeapiClient: pokeClient,
 		Pokedex:       make(map[string][]Pokemon),
-		Inventory:     defaultInventory(),
+		Bag:           defaultBag(),
 		UserName:      userName,
 		StoragePath:   storagePath,
 	}
@@ -33,19 +33,35 @@ func main() {
 		if _, err := os.Stat(storagePath); err == nil {
 			dataExists = true
 		}
-		loaded, inventory, lastDaily, err := loadUserData(storagePath)
-		if err != nil {
-			fmt.Printf("Warning: failed to load data: %v\n", err)
+		loaded, loadErr := loadUserData(storagePath)
+		if loadErr != nil {
+			fmt.Printf("Warning: failed to load data: %v\n", loadErr)
 		} else {
-			c.Pokedex = loaded
-			if inventory != (Inventory{}) {
-				c.Inventory = inventory
+			applyLoadedUserData(c, loaded)
+			if err := backfillMoveDetails(c); err != nil {
+				fmt.Printf("Warning: failed to look up saved moves: %v\n", err)
 			}
-			c.LastDailyGrant = lastDaily
+		}
+		if !dataExists {
+			version, err := promptVersion()
+			if err != nil {
+				fmt.Printf("Warning: failed to read game version: %v, using %s\n", err, defaultVersion)
+				version = defaultVersion
+			}
+			c.Version = version
 		}
 		if err := ensureStarterPokemon(c, dataExists); err != nil {
 			fmt.Printf("Warning: failed to add starter: %v\n", err)
 		}
+		ensureParty(c)
+		// Loading fills in what older saves lack, like UIDs and a party.
+		// Save them now so the next launch sees the same Pokemon. A save that
+		// failed to load is left alone.
+		if loadErr == nil {
+			if err := saveUserData(c); err != nil {
+				fmt.Printf("Warning: failed to save data: %v\n", err)
+			}
+		}
 	}
 
 	fmt.Printf("Using trainer: %s\n", userName)
@@ -0,0 +1,50 @@
+package main
+
+const defaultMovePP = 20
+
+const struggleMoveName = "struggle"
+
+func struggleMove() PokemonMove {
+	return PokemonMove{name: struggleMoveName, power: 50, accuracy: 0, priority: 0, moveType: "typeless"}
+}
+
+func moveUsable(move PokemonMove) bool {
+	return move.maxPP <= 0 || move.pp > 0
+}
+
+func hasUsableMove(moves []PokemonMove) bool {
+	for _, move := range moves {
+		if moveUsable(move) {
+			return true
+		}
+	}
+	return false
+}
+
+func spendPP(pokemon *Pokemon, name string) {
+	if pokemon == nil {
+		return
+	}
+	for i := range pokemon.moves {
+		if pokemon.moves[i].name != name {
+			continue
+		}
+		if pokemon.moves[i].maxPP > 0 && pokemon.moves[i].pp > 0 {
+			pokemon.moves[i].pp--
+		}
+		return
+	}
+}
+
+func restorePP(pokemon *Pokemon) {
+	if pokemon == nil {
+		return
+	}
+	for i := range pokemon.moves {
+		pokemon.moves[i].pp = pokemon.moves[i].maxPP
+	}
+}
+
+func struggleRecoil(attacker *battlePokemon) int {
+	return max(1, attacker.max/4)
+}
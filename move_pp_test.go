@@ -0,0 +1,40 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSpendAndRestorePP(t *testing.T) {
+	poke := Pokemon{moves: []PokemonMove{{name: "tackle", pp: 2, maxPP: 35}}}
+	spendPP(&poke, "tackle")
+	spendPP(&poke, "tackle")
+	spendPP(&poke, "tackle")
+	if poke.moves[0].pp != 0 {
+		t.Fatalf("expected PP to stop at 0, got %d", poke.moves[0].pp)
+	}
+	restorePP(&poke)
+	if poke.moves[0].pp != 35 {
+		t.Fatalf("expected PP restored to 35, got %d", poke.moves[0].pp)
+	}
+}
+
+func TestChooseWildMoveFallsBackToStruggle(t *testing.T) {
+	poke := Pokemon{moves: []PokemonMove{{name: "tackle", pp: 0, maxPP: 35}, {name: "growl", pp: 0, maxPP: 40}}}
+	move := chooseWildMove(rand.New(rand.NewSource(1)), poke)
+	if move.name != struggleMoveName {
+		t.Fatalf("expected struggle, got %s", move.name)
+	}
+}
+
+func TestStruggleRecoil(t *testing.T) {
+	attacker := battlePokemon{pokemon: Pokemon{name: "pidgey"}, current: 40, max: 40}
+	defender := battlePokemon{pokemon: Pokemon{name: "rattata"}, current: 40, max: 40}
+	events := resolveAttack(rand.New(rand.NewSource(1)), &attacker, &defender, struggleMove())
+	if len(events) != 2 || events[1].Kind != eventRecoil {
+		t.Fatalf("expected attack and recoil events, got %+v", events)
+	}
+	if attacker.current != 30 {
+		t.Fatalf("expected 10 recoil damage, attacker HP %d", attacker.current)
+	}
+}
@@ -0,0 +1,504 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/dey12956/pokedexcli/internal/pokeapi"
+)
+
+// netProtocolVersion is bumped whenever a message changes shape. Both ends
+// must speak the same version.
+const netProtocolVersion = 2
+
+const (
+	netHandshakeTimeout = 30 * time.Second
+	netTurnTimeout      = 2 * time.Minute
+	netWaitTimeout      = 10 * time.Minute
+	netWriteTimeout     = 10 * time.Second
+)
+
+const (
+	msgHello   = "hello"
+	msgWelcome = "welcome"
+	msgRequest = "request"
+	msgChoice  = "choice"
+	msgInvalid = "invalid"
+	msgEvents  = "events"
+	msgResult  = "result"
+	msgError   = "error"
+)
+
+const (
+	requestSendOut = "send-out"
+	requestTurn    = "turn"
+)
+
+const (
+	winnerHost  = "host"
+	winnerGuest = "guest"
+)
+
+var errPeerLost = errors.New("lost the connection to the other trainer")
+
+// netMessage is one line of the protocol. Which fields are set depends on
+// Type.
+type netMessage struct {
+	Type     string          `json:"type"`
+	Version  int             `json:"version,omitempty"`
+	Trainer  string          `json:"trainer,omitempty"`
+	Team     []pokemonRecord `json:"team,omitempty"`
+	Request  string          `json:"request,omitempty"`
+	You      *netSide        `json:"you,omitempty"`
+	Opponent *netSide        `json:"opponent,omitempty"`
+	Action   string          `json:"action,omitempty"`
+	Move     string          `json:"move,omitempty"`
+	Index    int             `json:"index,omitempty"`
+	Events   []battleEvent   `json:"events,omitempty"`
+	Winner   string          `json:"winner,omitempty"`
+	Record   *battleRecord   `json:"record,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// netSide is the battle state a guest needs to make a choice. The
+// opponent's side only shows its active Pokemon.
+type netSide struct {
+	Name      string      `json:"name"`
+	Label     string      `json:"label"`
+	Active    int         `json:"active"`
+	Remaining int         `json:"remaining"`
+	Team      []netMember `json:"team"`
+}
+
+type netMember struct {
+	Pokemon pokemonRecord `json:"pokemon"`
+	HP      int           `json:"hp"`
+	Stages  statStages    `json:"stages,omitempty"`
+}
+
+type netConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func newNetConn(conn net.Conn) *netConn {
+	return &netConn{conn: conn, reader: bufio.NewReader(conn)}
+}
+
+func (n *netConn) send(msg netMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	n.conn.SetWriteDeadline(time.Now().Add(netWriteTimeout))
+	if _, err := n.conn.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("%w: %v", errPeerLost, err)
+	}
+	return nil
+}
+
+// receive reads the next message, giving up after timeout. An error message
+// from the other end is returned as an error.
+func (n *netConn) receive(timeout time.Duration) (netMessage, error) {
+	n.conn.SetReadDeadline(time.Now().Add(timeout))
+	line, err := n.reader.ReadBytes('\n')
+	if err != nil {
+		return netMessage{}, fmt.Errorf("%w: %v", errPeerLost, err)
+	}
+	var msg netMessage
+	if err := json.Unmarshal(line, &msg); err != nil {
+		return netMessage{}, fmt.Errorf("bad message from the other trainer: %v", err)
+	}
+	if msg.Type == msgError {
+		return netMessage{}, fmt.Errorf("the other trainer ended the battle: %s", msg.Error)
+	}
+	return msg, nil
+}
+
+func (n *netConn) expect(msgType string, timeout time.Duration) (netMessage, error) {
+	msg, err := n.receive(timeout)
+	if err != nil {
+		return netMessage{}, err
+	}
+	if msg.Type != msgType {
+		return netMessage{}, fmt.Errorf("expected a %s message, got %s", msgType, msg.Type)
+	}
+	return msg, nil
+}
+
+// hostBattle runs the battle on the host, which owns the random source and
+// decides the result. winner is 0 for the host, 1 for the guest and -1 for
+// a draw. A guest who disconnects or times out mid-battle loses.
+func hostBattle(conn net.Conn, controller pvpController, c *config, team []Pokemon, rec *battleRecorder, seed int64) (winner int, opponent string, err error) {
+	peer := newNetConn(conn)
+	hello, err := peer.expect(msgHello, netHandshakeTimeout)
+	if err != nil {
+		return -1, "", err
+	}
+	if hello.Version != netProtocolVersion {
+		peer.send(netMessage{Type: msgError, Error: fmt.Sprintf("host speaks protocol version %d", netProtocolVersion)})
+		return -1, "", fmt.Errorf("the other trainer speaks protocol version %d, not %d", hello.Version, netProtocolVersion)
+	}
+	guestTeam, err := teamFromRecords(c, hello.Team)
+	if err != nil {
+		peer.send(netMessage{Type: msgError, Error: err.Error()})
+		return -1, "", err
+	}
+	if err := peer.send(netMessage{Type: msgWelcome, Version: netProtocolVersion, Trainer: c.UserName, Team: teamRecords(team)}); err != nil {
+		return -1, "", err
+	}
+
+	remote := &remoteController{peer: peer, rec: rec}
+	players := [2]*pvpPlayer{
+		{name: c.UserName, side: newBattleSide(sidePlayer, c.UserName+"'s ", team), controller: controller},
+		{name: hello.Trainer, side: newBattleSide(sideTrainer, hello.Trainer+"'s ", guestTeam), controller: remote},
+	}
+	rec.record.Opponent = hello.Trainer
+	b := &pvpBattle{r: rand.New(rand.NewSource(seed)), rec: rec, players: players}
+	rec.say(eventIntro, "%s challenges %s!", hello.Trainer, c.UserName)
+	winner, err = b.run()
+	if err != nil {
+		if !errors.Is(err, errPeerLost) {
+			peer.send(netMessage{Type: msgError, Error: "the host left the battle"})
+			return -1, hello.Trainer, err
+		}
+		rec.say(eventRun, "%s disconnected.", hello.Trainer)
+		winner = 0
+	}
+
+	b.announceResult(winner)
+	record := rec.record
+	record.Result = rec.result
+	result := netMessage{Type: msgResult, Record: &record}
+	switch winner {
+	case 0:
+		result.Winner = winnerHost
+	case 1:
+		result.Winner = winnerGuest
+	}
+	if err := remote.flush(); err == nil {
+		peer.send(result)
+	}
+	return winner, hello.Trainer, nil
+}
+
+// joinBattle plays the guest's side: it sends the team, then answers the
+// host's requests until the host reports the result. rec ends up with the
+// host's record seen from the guest's side. winner is 0 for the guest, 1 for
+// the host and -1 for a draw.
+func joinBattle(conn net.Conn, controller pvpController, c *config, team []Pokemon, rec *battleRecorder) (winner int, opponent string, err error) {
+	peer := newNetConn(conn)
+	if err := peer.send(netMessage{Type: msgHello, Version: netProtocolVersion, Trainer: c.UserName, Team: teamRecords(team)}); err != nil {
+		return -1, "", err
+	}
+	welcome, err := peer.expect(msgWelcome, netHandshakeTimeout)
+	if err != nil {
+		return -1, "", err
+	}
+	if welcome.Version != netProtocolVersion {
+		return -1, "", fmt.Errorf("the host speaks protocol version %d, not %d", welcome.Version, netProtocolVersion)
+	}
+	fmt.Printf("Connected to %s!\n", welcome.Trainer)
+	rec.record.Opponent = welcome.Trainer
+
+	for {
+		msg, err := peer.receive(netWaitTimeout)
+		if err != nil {
+			return -1, welcome.Trainer, err
+		}
+		switch msg.Type {
+		case msgEvents:
+			for _, event := range mirroredEvents(msg.Events) {
+				rec.record.Events = append(rec.record.Events, event)
+				fmt.Println(event.Message)
+			}
+		case msgInvalid:
+			fmt.Println(msg.Error)
+		case msgRequest:
+			if msg.You == nil || msg.Opponent == nil {
+				return -1, welcome.Trainer, errors.New("the host sent an incomplete request")
+			}
+			choice, err := answerRequest(controller, msg)
+			if err != nil {
+				peer.send(netMessage{Type: msgError, Error: "the guest left the battle"})
+				return -1, welcome.Trainer, err
+			}
+			if err := peer.send(choice); err != nil {
+				return -1, welcome.Trainer, err
+			}
+		case msgResult:
+			if msg.Record != nil {
+				rec.record = msg.Record.mirrored()
+			}
+			switch msg.Winner {
+			case winnerGuest:
+				return 0, welcome.Trainer, nil
+			case winnerHost:
+				return 1, welcome.Trainer, nil
+			}
+			return -1, welcome.Trainer, nil
+		}
+	}
+}
+
+func answerRequest(controller pvpController, msg netMessage) (netMessage, error) {
+	you, opponent := msg.You.battleSide(), msg.Opponent.battleSide()
+	if msg.Request == requestSendOut {
+		index, err := controller.chooseSendOut(&you, &opponent)
+		if err != nil {
+			return netMessage{}, err
+		}
+		return netMessage{Type: msgChoice, Action: actionSwitch, Index: index}, nil
+	}
+	turn, err := controller.chooseTurn(&you, &opponent)
+	if err != nil {
+		return netMessage{}, err
+	}
+	switch {
+	case turn.forfeit:
+		return netMessage{Type: msgChoice, Action: actionRun}, nil
+	case turn.switchTo >= 0:
+		return netMessage{Type: msgChoice, Action: actionSwitch, Index: turn.switchTo}, nil
+	}
+	return netMessage{Type: msgChoice, Action: actionFight, Move: turn.move.name}, nil
+}
+
+// remoteController asks the guest for choices on the host's behalf and
+// checks them against the host's own battle state.
+type remoteController struct {
+	peer *netConn
+	rec  *battleRecorder
+	sent int
+}
+
+// flush sends the guest every battle event recorded since the last flush.
+func (r *remoteController) flush() error {
+	events := r.rec.record.Events[r.sent:]
+	if len(events) == 0 {
+		return nil
+	}
+	r.sent = len(r.rec.record.Events)
+	return r.peer.send(netMessage{Type: msgEvents, Events: events})
+}
+
+func (r *remoteController) request(kind string, side, opponent *battleSide) (netMessage, error) {
+	if err := r.flush(); err != nil {
+		return netMessage{}, err
+	}
+	you, foe := netSideFrom(side, false), netSideFrom(opponent, true)
+	if err := r.peer.send(netMessage{Type: msgRequest, Request: kind, You: &you, Opponent: &foe}); err != nil {
+		return netMessage{}, err
+	}
+	return r.peer.expect(msgChoice, netTurnTimeout)
+}
+
+func (r *remoteController) reject(reason string) error {
+	return r.peer.send(netMessage{Type: msgInvalid, Error: reason})
+}
+
+func (r *remoteController) chooseSendOut(side, opponent *battleSide) (int, error) {
+	for {
+		choice, err := r.request(requestSendOut, side, opponent)
+		if err != nil {
+			return 0, err
+		}
+		if canSendOut(side, choice.Index) {
+			return choice.Index, nil
+		}
+		if err := r.reject("That Pokemon can't be sent out."); err != nil {
+			return 0, err
+		}
+	}
+}
+
+func (r *remoteController) chooseTurn(side, opponent *battleSide) (pvpTurn, error) {
+	for {
+		choice, err := r.request(requestTurn, side, opponent)
+		if err != nil {
+			return pvpTurn{}, err
+		}
+		switch choice.Action {
+		case actionRun:
+			return pvpTurn{battleTurn: battleTurn{switchTo: -1}, forfeit: true}, nil
+		case actionSwitch:
+			if canSendOut(side, choice.Index) {
+				return pvpTurn{battleTurn: battleTurn{switchTo: choice.Index}}, nil
+			}
+		case actionFight:
+			if move, ok := findUsableMove(side.current().pokemon, choice.Move); ok {
+				return pvpTurn{battleTurn: battleTurn{move: move, switchTo: -1}}, nil
+			}
+		}
+		if err := r.reject("That choice isn't allowed."); err != nil {
+			return pvpTurn{}, err
+		}
+	}
+}
+
+func canSendOut(side *battleSide, index int) bool {
+	return index >= 0 && index < len(side.team) && index != side.active && side.team[index].current > 0
+}
+
+// findUsableMove looks up a move the Pokemon can use right now; Struggle is
+// only allowed once every move is out of PP.
+func findUsableMove(pokemon Pokemon, name string) (PokemonMove, bool) {
+	moves := availableMoves(pokemon)
+	if !hasUsableMove(moves) {
+		return struggleMove(), name == struggleMoveName
+	}
+	for _, move := range moves {
+		if move.name == name && moveUsable(move) {
+			return move, true
+		}
+	}
+	return PokemonMove{}, false
+}
+
+func netSideFrom(side *battleSide, hidden bool) netSide {
+	snapshot := netSide{Name: side.name, Active: side.active, Remaining: side.remaining()}
+	for i, member := range side.team {
+		if hidden && i != side.active {
+			continue
+		}
+		snapshot.Label = member.label
+		snapshot.Team = append(snapshot.Team, netMember{Pokemon: pokemonToRecord(member.pokemon), HP: member.current, Stages: member.stages})
+	}
+	if hidden && side.active >= 0 {
+		snapshot.Active = 0
+	}
+	return snapshot
+}
+
+func (s netSide) battleSide() battleSide {
+	side := battleSide{name: s.Name, active: s.Active}
+	for _, member := range s.Team {
+		battle := newBattlePokemon(recordToPokemon(member.Pokemon))
+		battle.current = member.HP
+		battle.side = s.Name
+		battle.label = s.Label
+		if member.Stages != nil {
+			battle.stages = member.Stages
+		}
+		side.team = append(side.team, battle)
+	}
+	return side
+}
+
+func teamRecords(team []Pokemon) []pokemonRecord {
+	records := make([]pokemonRecord, 0, len(team))
+	for _, pokemon := range team {
+		records = append(records, pokemonToRecord(pokemon))
+	}
+	return records
+}
+
+// teamFromRecords rebuilds a guest's team on the host. Species data, stats
+// and moves all come from PokeAPI; only the level, nature, IVs, EVs and
+// cosmetics are taken from the records, and only within their legal limits.
+func teamFromRecords(c *config, records []pokemonRecord) ([]Pokemon, error) {
+	if len(records) == 0 || len(records) > maxPartySize {
+		return nil, fmt.Errorf("a team needs 1 to %d Pokemon", maxPartySize)
+	}
+	team := make([]Pokemon, 0, len(records))
+	for _, record := range records {
+		pokemon, err := rebuildGuestPokemon(c, record)
+		if err != nil {
+			return nil, err
+		}
+		team = append(team, pokemon)
+	}
+	return team, nil
+}
+
+func rebuildGuestPokemon(c *config, record pokemonRecord) (Pokemon, error) {
+	if record.Level < 1 || record.Level > maxLevel {
+		return Pokemon{}, fmt.Errorf("%s has an invalid level", record.Name)
+	}
+	if _, ok := natures[record.Nature]; !ok {
+		return Pokemon{}, fmt.Errorf("%s has an unknown nature %q", record.Name, record.Nature)
+	}
+	resp, err := c.pokeapiClient.GetPokemon(record.Name)
+	if err != nil {
+		return Pokemon{}, fmt.Errorf("couldn't look up %s: %w", record.Name, err)
+	}
+	entries, err := guestLearnset(resp, record.Moves)
+	if err != nil {
+		return Pokemon{}, err
+	}
+	moves, err := fetchMoves(c, entries)
+	if err != nil {
+		return Pokemon{}, err
+	}
+
+	pokemon := pokemonFromResponse(resp)
+	pokemon.uid = record.UID
+	pokemon.level = record.Level
+	pokemon.nature = record.Nature
+	pokemon.ivs = clampedIVs(record.IVs)
+	pokemon.evs = clampedEVs(record.EVs)
+	pokemon.friendship = defaultFriendship
+	if record.Friendship != nil {
+		pokemon.friendship = max(0, min(maxFriendship, *record.Friendship))
+	}
+	pokemon.gender = record.Gender
+	pokemon.shiny = record.Shiny
+	if slices.Contains(pokemon.forms, record.Form) {
+		pokemon.form = record.Form
+	}
+	if lookupItem(record.HeldItem).category == itemCategoryInflict {
+		pokemon.heldItem = record.HeldItem
+	}
+	pokemon.moves = moves
+	recalculateStats(&pokemon)
+	healPokemon(&pokemon)
+	return pokemon, nil
+}
+
+// guestLearnset looks up the moves a guest says its Pokemon knows. Each one
+// must be a move the species can learn.
+func guestLearnset(resp pokeapi.CatchPokemonResponse, moves []pokemonMoveRecord) ([]learnsetEntry, error) {
+	if len(moves) == 0 || len(moves) > maxKnownMoves {
+		return nil, fmt.Errorf("%s needs 1 to %d moves", resp.Name, maxKnownMoves)
+	}
+	urls := make(map[string]string, len(resp.Moves))
+	for _, move := range resp.Moves {
+		urls[move.Move.Name] = move.Move.URL
+	}
+	entries := make([]learnsetEntry, 0, len(moves))
+	for _, move := range moves {
+		url, ok := urls[move.Name]
+		if !ok {
+			return nil, fmt.Errorf("%s can't learn %s", resp.Name, move.Name)
+		}
+		if slices.ContainsFunc(entries, func(entry learnsetEntry) bool { return entry.name == move.Name }) {
+			return nil, fmt.Errorf("%s knows %s twice", resp.Name, move.Name)
+		}
+		entries = append(entries, learnsetEntry{name: move.Name, url: url})
+	}
+	return entries, nil
+}
+
+func clampedIVs(ivs map[string]int) map[string]int {
+	clamped := make(map[string]int, len(statNames))
+	for _, stat := range statNames {
+		clamped[stat] = max(0, min(maxIV, ivs[stat]))
+	}
+	return clamped
+}
+
+func clampedEVs(evs map[string]int) map[string]int {
+	clamped := make(map[string]int, len(statNames))
+	total := 0
+	for _, stat := range statNames {
+		ev := max(0, min(maxStatEV, evs[stat], maxTotalEV-total))
+		clamped[stat] = ev
+		total += ev
+	}
+	return clamped
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dey12956/pokedexcli/internal/pokeapi"
+)
+
+// scriptedController always leads with the first able Pokemon and uses its
+// first move.
+type scriptedController struct{}
+
+func (scriptedController) chooseSendOut(side, opponent *battleSide) (int, error) {
+	return nextAbleMember(side), nil
+}
+
+func (scriptedController) chooseTurn(side, opponent *battleSide) (pvpTurn, error) {
+	return pvpTurn{battleTurn: battleTurn{move: side.current().pokemon.moves[0], switchTo: -1}}, nil
+}
+
+func netTestPokemon(name string, level, attack int) Pokemon {
+	pokemon := Pokemon{
+		uid:    name,
+		name:   name,
+		level:  level,
+		nature: "hardy",
+		types:  []string{"normal"},
+		stats:  map[string]int{"hp": 60, "attack": attack, "defense": 40, "speed": 50},
+		moves:  []PokemonMove{{name: "tackle", power: 40, accuracy: 100, pp: 35, maxPP: 35, moveType: "normal"}},
+	}
+	healPokemon(&pokemon)
+	return pokemon
+}
+
+// netTestAPI serves canned PokeAPI responses by URL path.
+type netTestAPI map[string]string
+
+func (api netTestAPI) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, ok := api[req.URL.Path]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Request: req}, nil
+}
+
+// netTestHost is a host that can look up rattata and its moves.
+func netTestHost() *config {
+	api := netTestAPI{
+		"/api/v2/pokemon/rattata": `{"name":"rattata","species":{"name":"rattata"},"types":[{"slot":1,"type":{"name":"normal"}}],` +
+			`"stats":[{"base_stat":30,"stat":{"name":"hp"}},{"base_stat":56,"stat":{"name":"attack"}},{"base_stat":35,"stat":{"name":"defense"}},` +
+			`{"base_stat":25,"stat":{"name":"special-attack"}},{"base_stat":35,"stat":{"name":"special-defense"}},{"base_stat":72,"stat":{"name":"speed"}}],` +
+			`"moves":[{"move":{"name":"tackle","url":"https://pokeapi.co/api/v2/move/33/"}}]}`,
+		"/api/v2/move/33/": `{"name":"tackle","power":40,"accuracy":100,"pp":35,"priority":0,"type":{"name":"normal"},"damage_class":{"name":"physical"},"target":{"name":"selected-pokemon"}}`,
+	}
+	return &config{UserName: "ash", pokeapiClient: pokeapi.NewClientWithTransport(api, time.Second, time.Minute)}
+}
+
+func TestNetBattleOverLoopback(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer listener.Close()
+
+	type outcome struct {
+		winner   int
+		opponent string
+		err      error
+		rec      *battleRecorder
+	}
+	guestDone := make(chan outcome, 1)
+	go func() {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		if err != nil {
+			guestDone <- outcome{err: err}
+			return
+		}
+		defer conn.Close()
+		guest := &config{UserName: "gary"}
+		rec := newBattleRecorder(guest, 0)
+		winner, opponent, err := joinBattle(conn, scriptedController{}, guest, []Pokemon{netTestPokemon("rattata", 5, 20)}, rec)
+		guestDone <- outcome{winner, opponent, err, rec}
+	}()
+
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer conn.Close()
+	host := netTestHost()
+	rec := newBattleRecorder(host, 3)
+	winner, opponent, err := hostBattle(conn, scriptedController{}, host, []Pokemon{netTestPokemon("machamp", 50, 130)}, rec, 3)
+	if err != nil {
+		t.Fatalf("unexpected host error: %v", err)
+	}
+	if winner != 0 || opponent != "gary" || rec.result != resultWin {
+		t.Fatalf("expected ash to beat gary, got winner %d opponent %q result %q", winner, opponent, rec.result)
+	}
+
+	guest := <-guestDone
+	if guest.err != nil {
+		t.Fatalf("unexpected guest error: %v", guest.err)
+	}
+	if guest.winner != 1 || guest.opponent != "ash" {
+		t.Fatalf("expected the guest to see ash win, got %+v", guest)
+	}
+	seen := guest.rec.record
+	if seen.Trainer != "gary" || seen.Opponent != "ash" || seen.Result != resultLoss || seen.Seed != 3 {
+		t.Fatalf("expected gary's record of a loss to ash, got %+v", seen)
+	}
+	if len(seen.Participants) != 2 || len(seen.Participants) != len(rec.record.Participants) || len(seen.Events) != len(rec.record.Events) {
+		t.Fatalf("expected the guest to receive all participants and events, got %+v", seen)
+	}
+	for _, participant := range seen.Participants {
+		if want := map[string]string{"rattata": sidePlayer, "machamp": sideTrainer}[participant.Pokemon.Name]; participant.Side != want {
+			t.Fatalf("expected %s on the %s side, got %s", participant.Pokemon.Name, want, participant.Side)
+		}
+	}
+	lastRound := 0
+	for i, event := range seen.Events {
+		if event.Round != rec.record.Events[i].Round || event.Side != mirroredSide(rec.record.Events[i].Side) {
+			t.Fatalf("expected event %d mirrored from the host's, got %+v", i, event)
+		}
+		if event.Kind == eventAttack && event.Pokemon == "machamp" && event.Side != sideTrainer {
+			t.Fatalf("expected the host's attacks on the trainer side, got %+v", event)
+		}
+		lastRound = max(lastRound, event.Round)
+	}
+	if lastRound == 0 {
+		t.Fatalf("expected the events to keep their rounds")
+	}
+}
+
+func TestNetBattleGuestDisconnectLoses(t *testing.T) {
+	hostConn, guestConn := net.Pipe()
+	go func() {
+		peer := newNetConn(guestConn)
+		peer.send(netMessage{Type: msgHello, Version: netProtocolVersion, Trainer: "gary", Team: teamRecords([]Pokemon{netTestPokemon("rattata", 5, 20)})})
+		peer.expect(msgWelcome, netHandshakeTimeout)
+		guestConn.Close()
+	}()
+
+	host := netTestHost()
+	winner, _, err := hostBattle(hostConn, scriptedController{}, host, []Pokemon{netTestPokemon("machamp", 50, 130)}, newBattleRecorder(host, 1), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if winner != 0 {
+		t.Fatalf("expected the host to win by disconnect, got %d", winner)
+	}
+}
+
+func TestNetBattleRejectsOtherVersions(t *testing.T) {
+	hostConn, guestConn := net.Pipe()
+	go func() {
+		peer := newNetConn(guestConn)
+		peer.send(netMessage{Type: msgHello, Version: netProtocolVersion + 1, Trainer: "gary", Team: teamRecords([]Pokemon{netTestPokemon("rattata", 5, 20)})})
+		peer.receive(netHandshakeTimeout)
+		guestConn.Close()
+	}()
+
+	host := &config{UserName: "ash"}
+	if _, _, err := hostBattle(hostConn, scriptedController{}, host, nil, newBattleRecorder(host, 1), 1); err == nil {
+		t.Fatalf("expected a version mismatch error")
+	}
+}
+
+func TestNetBattleRebuildsTamperedTeam(t *testing.T) {
+	cheat := netTestPokemon("rattata", 5, 999)
+	cheat.stats = map[string]int{"hp": 999, "attack": 999, "defense": 999, "speed": 999}
+	cheat.baseStats = map[string]int{"hp": 255, "attack": 255, "defense": 255, "speed": 255}
+	cheat.ivs = map[string]int{"hp": 99, "attack": 99}
+	cheat.evs = map[string]int{"hp": 255, "attack": 255, "defense": 255}
+	cheat.moves[0].power = 999
+	cheat.moves[0].priority = 5
+	healPokemon(&cheat)
+
+	hostConn, guestConn := net.Pipe()
+	go func() {
+		defer guestConn.Close()
+		guest := &config{UserName: "gary"}
+		joinBattle(guestConn, scriptedController{}, guest, []Pokemon{cheat}, newBattleRecorder(guest, 0))
+	}()
+
+	host := netTestHost()
+	winner, _, err := hostBattle(hostConn, scriptedController{}, host, []Pokemon{netTestPokemon("machamp", 50, 130)}, newBattleRecorder(host, 2), 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if winner != 0 {
+		t.Fatalf("expected the rebuilt rattata to lose, got winner %d", winner)
+	}
+}
+
+func TestTeamFromRecordsRebuildsFromPokeAPI(t *testing.T) {
+	host := netTestHost()
+	record := pokemonToRecord(netTestPokemon("rattata", 5, 999))
+	record.Moves[0].Power = 999
+	record.IVs = map[string]int{"hp": 99, "attack": -4}
+	record.EVs = map[string]int{"hp": 300, "attack": 300, "defense": 300}
+
+	team, err := teamFromRecords(host, []pokemonRecord{record})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rattata := team[0]
+	if rattata.baseStats["attack"] != 56 || rattata.moves[0].power != 40 {
+		t.Fatalf("expected species data and moves from PokeAPI, got %v and %+v", rattata.baseStats, rattata.moves[0])
+	}
+	if rattata.ivs["hp"] != maxIV || rattata.ivs["attack"] != 0 {
+		t.Fatalf("expected IVs clamped to 0-%d, got %v", maxIV, rattata.ivs)
+	}
+	if rattata.evs["hp"] != maxStatEV || rattata.evs["attack"] != maxStatEV || rattata.evs["defense"] != maxTotalEV-2*maxStatEV {
+		t.Fatalf("expected EVs clamped to the per-stat and total limits, got %v", rattata.evs)
+	}
+
+	tests := map[string]func(*pokemonRecord){
+		"unknown nature":  func(r *pokemonRecord) { r.Nature = "sneaky" },
+		"unlearned move":  func(r *pokemonRecord) { r.Moves[0].Name = "sacred-fire" },
+		"repeated move":   func(r *pokemonRecord) { r.Moves = append(r.Moves, r.Moves[0]) },
+		"unknown species": func(r *pokemonRecord) { r.Name = "missingno" },
+	}
+	for name, tamper := range tests {
+		record := pokemonToRecord(netTestPokemon("rattata", 5, 20))
+		tamper(&record)
+		if _, err := teamFromRecords(host, []pokemonRecord{record}); err == nil {
+			t.Errorf("%s: expected the team to be rejected", name)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+const maxPartySize = 6
+
+func newPokemonUID() string {
+	return strconv.FormatInt(rng.Int63(), 36)
+}
+
+func findOwnedPokemon(c *config, uid string) (pokemonSelection, bool) {
+	if c == nil || uid == "" {
+		return pokemonSelection{}, false
+	}
+	for key, entries := range c.Pokedex {
+		for index, entry := range entries {
+			if entry.uid == uid {
+				return pokemonSelection{key: key, index: index, label: pokemonLabel(entry)}, true
+			}
+		}
+	}
+	return pokemonSelection{}, false
+}
+
+func pokemonLabel(pokemon Pokemon) string {
+	name := pokemon.name
+	if form := formLabel(pokemon); form != "" {
+		name = fmt.Sprintf("%s [%s]", name, form)
+	}
+	return fmt.Sprintf("%s%s (Lv %d)", name, genderSymbol(pokemon.gender), pokemon.level)
+}
+
+func partyPokemon(c *config) []Pokemon {
+	members := make([]Pokemon, 0, len(c.Party))
+	for _, uid := range c.Party {
+		if selection, ok := findOwnedPokemon(c, uid); ok {
+			members = append(members, c.Pokedex[selection.key][selection.index])
+		}
+	}
+	return members
+}
+
+func partyCanBattle(members []Pokemon) bool {
+	for _, member := range members {
+		if !member.fainted {
+			return true
+		}
+	}
+	return false
+}
+
+func inParty(c *config, uid string) bool {
+	for _, member := range c.Party {
+		if member == uid {
+			return true
+		}
+	}
+	return false
+}
+
+func addToParty(c *config, pokemon Pokemon) bool {
+	if c == nil || pokemon.uid == "" || len(c.Party) >= maxPartySize || inParty(c, pokemon.uid) {
+		return false
+	}
+	c.Party = append(c.Party, pokemon.uid)
+	return true
+}
+
+// ensureParty drops members that are no longer owned and fills an empty
+// party (for example from an older save) with the highest level Pokemon.
+func ensureParty(c *config) {
+	if c == nil {
+		return
+	}
+	valid := make([]string, 0, len(c.Party))
+	for _, uid := range c.Party {
+		if _, ok := findOwnedPokemon(c, uid); ok && !contains(valid, uid) && len(valid) < maxPartySize {
+			valid = append(valid, uid)
+		}
+	}
+	c.Party = valid
+	if len(c.Party) > 0 {
+		return
+	}
+
+	owned := make([]Pokemon, 0)
+	for _, entries := range c.Pokedex {
+		owned = append(owned, entries...)
+	}
+	sort.SliceStable(owned, func(i, j int) bool {
+		if owned[i].level != owned[j].level {
+			return owned[i].level > owned[j].level
+		}
+		return owned[i].name < owned[j].name
+	})
+	for _, pokemon := range owned {
+		if !addToParty(c, pokemon) {
+			break
+		}
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func catchIntoPokedex(c *config, pokemon Pokemon) {
+	appendCaughtPokemon(c, pokemon)
+	if addToParty(c, pokemon) {
+		fmt.Printf("%s joined your party.\n", pokemon.name)
+	} else {
+		fmt.Printf("Your party is full, %s was sent to storage.\n", pokemon.name)
+	}
+}
+
+// selectOwnedPokemon resolves "<pokemon> [number]" arguments to an owned
+// Pokemon. Without a number, a party member of that species is preferred.
+func selectOwnedPokemon(c *config, args ...string) (pokemonSelection, error) {
+	return selectOwnedPokemonBy(c, func(pokemon Pokemon) bool { return inParty(c, pokemon.uid) }, args...)
+}
+
+// selectOwnedPokemonBy is selectOwnedPokemon with, when there's no number,
+// the first Pokemon of the species that prefer accepts picked instead.
+func selectOwnedPokemonBy(c *config, prefer func(Pokemon) bool, args ...string) (pokemonSelection, error) {
+	if len(args) == 0 || len(args) > 2 {
+		return pokemonSelection{}, errors.New("Enter a Pokemon name and optional number")
+	}
+	entries, exists := c.Pokedex[args[0]]
+	if !exists || len(entries) == 0 {
+		return pokemonSelection{}, errors.New("You have not caught that pokemon")
+	}
+	if len(args) == 2 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 || n > len(entries) {
+			return pokemonSelection{}, fmt.Errorf("Enter a number between 1 and %d", len(entries))
+		}
+		return pokemonSelection{key: args[0], index: n - 1, label: pokemonLabel(entries[n-1])}, nil
+	}
+	for i, entry := range entries {
+		if prefer(entry) {
+			return pokemonSelection{key: args[0], index: i, label: pokemonLabel(entry)}, nil
+		}
+	}
+	return pokemonSelection{key: args[0], index: 0, label: pokemonLabel(entries[0])}, nil
+}
+
+func partyIndex(c *config, arg string) (int, error) {
+	slot, err := strconv.Atoi(arg)
+	if err != nil || slot < 1 || slot > len(c.Party) {
+		return 0, fmt.Errorf("Enter a party slot between 1 and %d", len(c.Party))
+	}
+	return slot - 1, nil
+}
+
+var errPartyFull = errors.New("Your party already has 6 Pokemon")
@@ -0,0 +1,57 @@
+package main
+
+import "testing"
+
+func TestEnsurePartyFillsFromHighestLevel(t *testing.T) {
+	c := &config{Pokedex: map[string][]Pokemon{
+		"pidgey":  {{uid: "a", name: "pidgey", level: 3}, {uid: "b", name: "pidgey", level: 9}},
+		"rattata": {{uid: "c", name: "rattata", level: 5}},
+	}}
+	ensureParty(c)
+	want := []string{"b", "c", "a"}
+	if len(c.Party) != len(want) {
+		t.Fatalf("expected party %v, got %v", want, c.Party)
+	}
+	for i := range want {
+		if c.Party[i] != want[i] {
+			t.Fatalf("expected party %v, got %v", want, c.Party)
+		}
+	}
+}
+
+func TestEnsurePartyDropsUnknownMembers(t *testing.T) {
+	c := &config{
+		Pokedex: map[string][]Pokemon{"pidgey": {{uid: "a", name: "pidgey", level: 3}}},
+		Party:   []string{"gone", "a", "a"},
+	}
+	ensureParty(c)
+	if len(c.Party) != 1 || c.Party[0] != "a" {
+		t.Fatalf("expected only the owned member to remain, got %v", c.Party)
+	}
+}
+
+func TestAddToPartyRespectsLimit(t *testing.T) {
+	c := &config{Party: []string{"1", "2", "3", "4", "5", "6"}}
+	if addToParty(c, Pokemon{uid: "7"}) {
+		t.Fatalf("expected a full party to reject new members")
+	}
+}
+
+func TestPartyAddPicksAMemberNotInTheParty(t *testing.T) {
+	c := &config{
+		Pokedex: map[string][]Pokemon{"pidgey": {{uid: "a", name: "pidgey", level: 3}, {uid: "b", name: "pidgey", level: 9}}},
+		Party:   []string{"a"},
+	}
+	if selection, err := selectOwnedPokemon(c, "pidgey"); err != nil || selection.index != 0 {
+		t.Fatalf("expected the party member to be selected, got %+v %v", selection, err)
+	}
+	if err := commandParty(c, "add", "pidgey"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(c.Party) != 2 || c.Party[1] != "b" {
+		t.Fatalf("expected the pidgey outside the party to join, got %v", c.Party)
+	}
+	if err := commandParty(c, "add", "pidgey", "3"); err == nil {
+		t.Fatalf("expected an out of range number to be rejected")
+	}
+}
@@ -7,9 +7,52 @@ import (
 )
 
 func buildPokemonFromResponse(c *config, resp pokeapi.CatchPokemonResponse) (Pokemon, error) {
-	stats := make(map[string]int)
+	speciesResp, err := c.pokeapiClient.GetPokemonSpecies(resp.Species.Name)
+	if err != nil {
+		return Pokemon{}, err
+	}
+
+	level, err := levelForExperience(c, speciesResp.GrowthRate.URL, resp.BaseExperience)
+	if err != nil {
+		return Pokemon{}, err
+	}
+	friendship := defaultFriendship
+	if speciesResp.BaseHappiness != nil {
+		friendship = *speciesResp.BaseHappiness
+	}
+	moves, err := fetchMoves(c, latestMoves(levelUpLearnset(resp, versionGroup(c)), level))
+	if err != nil {
+		return Pokemon{}, err
+	}
+
+	pokemon := pokemonFromResponse(resp)
+	pokemon.uid = newPokemonUID()
+	pokemon.dateCaught = time.Now()
+	pokemon.friendship = friendship
+	pokemon.gender = rollGender(rng, speciesResp.GenderRate)
+	pokemon.form = rollForm(rng, pokemon.forms)
+	pokemon.moves = moves
+	pokemon.level = level
+	pokemon.experience = resp.BaseExperience
+	pokemon.growthRate = speciesResp.GrowthRate.URL
+	pokemon.evolutionChain = speciesResp.EvolutionChain.URL
+	pokemon.lastXPAt = time.Now()
+	pokemon.lastXPGain = resp.BaseExperience
+	rollIndividual(rng, &pokemon)
+	restoreHP(&pokemon)
+	return pokemon, nil
+}
+
+// pokemonFromResponse fills in the species data PokeAPI has for a Pokemon,
+// leaving everything specific to one caught Pokemon unset.
+func pokemonFromResponse(resp pokeapi.CatchPokemonResponse) Pokemon {
+	baseStats := make(map[string]int)
+	effortYield := make(map[string]int)
 	for _, stat := range resp.Stats {
-		stats[stat.Stat.Name] = stat.BaseStat
+		baseStats[stat.Stat.Name] = stat.BaseStat
+		if stat.Effort > 0 {
+			effortYield[stat.Stat.Name] = stat.Effort
+		}
 	}
 
 	types := make([]string, 0, len(resp.Types))
@@ -36,45 +79,12 @@ func buildPokemonFromResponse(c *config, resp pokeapi.CatchPokemonResponse) (Pok
 		forms = append(forms, form.Name)
 	}
 
-	moves := make([]PokemonMove, 0, len(resp.Moves))
-	for _, move := range resp.Moves {
-		moveResp, err := c.pokeapiClient.GetMove(move.Move.URL)
-		if err != nil {
-			return Pokemon{}, err
-		}
-		power := 0
-		if moveResp.Power != nil {
-			power = *moveResp.Power
-		}
-		accuracy := 0
-		if moveResp.Accuracy != nil {
-			accuracy = *moveResp.Accuracy
-		}
-		moves = append(moves, PokemonMove{
-			name:     moveResp.Name,
-			power:    power,
-			accuracy: accuracy,
-			priority: moveResp.Priority,
-			moveType: moveResp.Type.Name,
-		})
-	}
-
-	speciesResp, err := c.pokeapiClient.GetPokemonSpecies(resp.Species.Name)
-	if err != nil {
-		return Pokemon{}, err
-	}
-
-	level, err := levelForExperience(c, speciesResp.GrowthRate.URL, resp.BaseExperience)
-	if err != nil {
-		return Pokemon{}, err
-	}
-
 	return Pokemon{
 		name:           resp.Name,
-		dateCaught:     time.Now(),
 		height:         resp.Height,
 		weight:         resp.Weight,
-		stats:          stats,
+		baseStats:      baseStats,
+		effortYield:    effortYield,
 		types:          types,
 		id:             resp.ID,
 		baseExperience: resp.BaseExperience,
@@ -84,13 +94,45 @@ func buildPokemonFromResponse(c *config, resp pokeapi.CatchPokemonResponse) (Pok
 		abilities:      abilities,
 		heldItems:      heldItems,
 		forms:          forms,
-		moves:          moves,
 		moveCount:      len(resp.Moves),
-		level:          level,
-		experience:     resp.BaseExperience,
-		growthRate:     speciesResp.GrowthRate.URL,
-		evolutionChain: speciesResp.EvolutionChain.URL,
-		lastXPAt:       time.Now(),
-		lastXPGain:     resp.BaseExperience,
-	}, nil
+	}
+}
+
+func buildMove(moveResp pokeapi.MoveResponse) PokemonMove {
+	power := 0
+	if moveResp.Power != nil {
+		power = *moveResp.Power
+	}
+	accuracy := 0
+	if moveResp.Accuracy != nil {
+		accuracy = *moveResp.Accuracy
+	}
+	pp := 0
+	if moveResp.PP != nil {
+		pp = *moveResp.PP
+	}
+	statChanges := make([]moveStatChange, 0, len(moveResp.StatChanges))
+	for _, change := range moveResp.StatChanges {
+		statChanges = append(statChanges, moveStatChange{stat: change.Stat.Name, change: change.Change})
+	}
+	statChance := 0
+	category := ""
+	if moveResp.Meta != nil {
+		statChance = moveResp.Meta.StatChance
+		category = moveResp.Meta.Category.Name
+	}
+	return PokemonMove{
+		name:        moveResp.Name,
+		power:       power,
+		accuracy:    accuracy,
+		priority:    moveResp.Priority,
+		moveType:    moveResp.Type.Name,
+		pp:          pp,
+		maxPP:       pp,
+		damageClass: moveResp.DamageClass.Name,
+		target:      moveResp.Target.Name,
+		statChance:  statChance,
+		statChanges: statChanges,
+		category:    category,
+	}
 }
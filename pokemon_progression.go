@@ -5,8 +5,6 @@ import (
 	"math"
 	"strings"
 	"time"
-
-	"github.com/dey12956/pokedexcli/internal/pokeapi"
 )
 
 const maxLevel = 100
@@ -34,6 +32,23 @@ func levelForExperience(c *config, growthRateURL string, experience int) (int, e
 	return level, nil
 }
 
+// experienceForLevel returns the least experience needed to reach level.
+func experienceForLevel(c *config, growthRateURL string, level int) (int, error) {
+	if strings.TrimSpace(growthRateURL) == "" {
+		return 0, nil
+	}
+	resp, err := c.pokeapiClient.GetGrowthRate(growthRateURL)
+	if err != nil {
+		return 0, err
+	}
+	for _, entry := range resp.Levels {
+		if entry.Level == level {
+			return entry.Experience, nil
+		}
+	}
+	return 0, errors.New("Level not found in growth rate")
+}
+
 func applyRestXP(c *config, pokemon *Pokemon) error {
 	if pokemon == nil || pokemon.lastXPAt.IsZero() || pokemon.lastXPGain <= 0 {
 		return nil
@@ -71,22 +86,32 @@ func applyExperience(c *config, pokemon *Pokemon, gained int, updateLastGain boo
 		pokemon.level = maxLevel
 		return nil
 	}
+	levelLimit := levelCap(c)
+	if pokemon.level >= levelLimit {
+		return nil
+	}
 
+	prevMax := maxHP(*pokemon)
 	pokemon.experience += gained
 	level, err := levelForExperience(c, pokemon.growthRate, pokemon.experience)
 	if err != nil {
 		return err
 	}
-	if level > maxLevel {
-		level = maxLevel
+	if level > levelLimit {
+		level = levelLimit
 	}
 	prevLevel := pokemon.level
-	pokemon.level = level
+	setLevel(pokemon, level)
+	adjustHPForMaxChange(pokemon, prevMax)
 	if updateLastGain {
 		pokemon.lastXPGain = gained
 		pokemon.lastXPAt = time.Now()
 	}
 	if pokemon.level > prevLevel {
+		changeFriendship(pokemon, friendshipLevelUp)
+		if err := learnLevelUpMoves(c, pokemon, prevLevel); err != nil {
+			return err
+		}
 		if err := maybeEvolve(c, pokemon); err != nil {
 			return err
 		}
@@ -96,20 +121,9 @@ func applyExperience(c *config, pokemon *Pokemon, gained int, updateLastGain boo
 	return nil
 }
 
-func maybeEvolve(c *config, pokemon *Pokemon) error {
-	if pokemon == nil || pokemon.evolutionChain == "" || pokemon.species == "" {
-		return nil
-	}
-	chain, err := c.pokeapiClient.GetEvolutionChain(pokemon.evolutionChain)
-	if err != nil {
-		return err
-	}
-
-	nextName, minLevel, found := findNextEvolution(chain.Chain, pokemon.species)
-	if !found || minLevel == 0 || pokemon.level < minLevel {
-		return nil
-	}
-
+// evolveInto replaces pokemon with the given species while keeping the
+// individual's progress (level, XP, HP and history).
+func evolveInto(c *config, pokemon *Pokemon, nextName string) error {
 	resp, err := c.pokeapiClient.GetPokemon(nextName)
 	if err != nil {
 		return err
@@ -119,44 +133,29 @@ func maybeEvolve(c *config, pokemon *Pokemon) error {
 		return err
 	}
 
+	updated.uid = pokemon.uid
 	updated.experience = pokemon.experience
 	updated.level = pokemon.level
+	updated.ivs = pokemon.ivs
+	updated.evs = pokemon.evs
+	updated.nature = pokemon.nature
+	updated.friendship = pokemon.friendship
+	updated.shiny = pokemon.shiny
+	updated.gender = pokemon.gender
+	updated.form = carryForm(pokemon.name, pokemon.form, updated.name, updated.forms)
+	updated.moves = pokemon.moves
+	recalculateStats(&updated)
 	updated.growthRate = pokemon.growthRate
 	updated.evolutionChain = pokemon.evolutionChain
 	updated.lastXPAt = pokemon.lastXPAt
 	updated.lastXPGain = pokemon.lastXPGain
 	updated.dateCaught = pokemon.dateCaught
+	updated.currentHP = pokemon.currentHP
+	updated.fainted = pokemon.fainted
+	adjustHPForMaxChange(&updated, maxHP(*pokemon))
 
 	*pokemon = updated
 	return nil
 }
 
-func findNextEvolution(link pokeapi.EvolutionChainLink, species string) (string, int, bool) {
-	if strings.EqualFold(link.Species.Name, species) {
-		minLevel := 0
-		var nextName string
-		for _, evolve := range link.EvolvesTo {
-			candidateLevel := 0
-			for _, detail := range evolve.EvolutionDetails {
-				if detail.MinLevel != nil && *detail.MinLevel > candidateLevel {
-					candidateLevel = *detail.MinLevel
-				}
-			}
-			if nextName == "" || (candidateLevel > 0 && candidateLevel < minLevel) || minLevel == 0 {
-				minLevel = candidateLevel
-				nextName = evolve.Species.Name
-			}
-		}
-		if nextName != "" {
-			return nextName, minLevel, true
-		}
-	}
-	for _, evolve := range link.EvolvesTo {
-		if nextName, minLevel, found := findNextEvolution(evolve, species); found {
-			return nextName, minLevel, true
-		}
-	}
-	return "", 0, false
-}
-
 var errNoPokemon = errors.New("no pokemon available")
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+)
+
+const (
+	maxIV      = 31
+	maxStatEV  = 252
+	maxTotalEV = 510
+)
+
+var statNames = []string{"hp", "attack", "defense", "special-attack", "special-defense", "speed"}
+
+// nature raises one stat by 10% and lowers another by 10%; the five neutral
+// natures leave both empty.
+type nature struct {
+	increased string
+	decreased string
+}
+
+var natures = map[string]nature{
+	"hardy":   {},
+	"lonely":  {"attack", "defense"},
+	"brave":   {"attack", "speed"},
+	"adamant": {"attack", "special-attack"},
+	"naughty": {"attack", "special-defense"},
+	"bold":    {"defense", "attack"},
+	"docile":  {},
+	"relaxed": {"defense", "speed"},
+	"impish":  {"defense", "special-attack"},
+	"lax":     {"defense", "special-defense"},
+	"timid":   {"speed", "attack"},
+	"hasty":   {"speed", "defense"},
+	"serious": {},
+	"jolly":   {"speed", "special-attack"},
+	"naive":   {"speed", "special-defense"},
+	"modest":  {"special-attack", "attack"},
+	"mild":    {"special-attack", "defense"},
+	"quiet":   {"special-attack", "speed"},
+	"bashful": {},
+	"rash":    {"special-attack", "special-defense"},
+	"calm":    {"special-defense", "attack"},
+	"gentle":  {"special-defense", "defense"},
+	"sassy":   {"special-defense", "speed"},
+	"careful": {"special-defense", "special-attack"},
+	"quirky":  {},
+}
+
+func natureNames() []string {
+	names := make([]string, 0, len(natures))
+	for name := range natures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func natureMultiplier(natureName, stat string) float64 {
+	n := natures[natureName]
+	switch {
+	case n.increased == "":
+		return 1
+	case stat == n.increased:
+		return 1.1
+	case stat == n.decreased:
+		return 0.9
+	}
+	return 1
+}
+
+// rollIndividual gives a freshly built Pokemon its own IVs and nature.
+func rollIndividual(r *rand.Rand, pokemon *Pokemon) {
+	pokemon.ivs = make(map[string]int, len(statNames))
+	for _, stat := range statNames {
+		pokemon.ivs[stat] = r.Intn(maxIV + 1)
+	}
+	names := natureNames()
+	pokemon.nature = names[r.Intn(len(names))]
+	pokemon.evs = make(map[string]int)
+	recalculateStats(pokemon)
+}
+
+// calculateStat is the main series formula for a single stat.
+func calculateStat(stat string, base, iv, ev, level int, natureName string) int {
+	value := (2*base + iv + ev/4) * level / 100
+	if stat == "hp" {
+		return value + level + 10
+	}
+	return int(float64(value+5) * natureMultiplier(natureName, stat))
+}
+
+// recalculateStats derives stats from base stats, IVs, EVs, level and
+// nature. Pokemon without base stats (built by hand) keep their stats.
+func recalculateStats(pokemon *Pokemon) {
+	if pokemon == nil || len(pokemon.baseStats) == 0 {
+		return
+	}
+	level := max(1, pokemon.level)
+	stats := make(map[string]int, len(pokemon.baseStats))
+	for stat, base := range pokemon.baseStats {
+		stats[stat] = calculateStat(stat, base, pokemon.ivs[stat], pokemon.evs[stat], level, pokemon.nature)
+	}
+	pokemon.stats = stats
+}
+
+// setLevel changes a Pokemon's level and the stats that depend on it.
+func setLevel(pokemon *Pokemon, level int) {
+	pokemon.level = level
+	recalculateStats(pokemon)
+}
+
+// gainEffort adds the EVs a defeated Pokemon yields, up to the per-stat and
+// total limits. It returns the EVs actually gained.
+func gainEffort(pokemon *Pokemon, yield map[string]int) int {
+	if pokemon == nil || len(yield) == 0 {
+		return 0
+	}
+	if pokemon.evs == nil {
+		pokemon.evs = make(map[string]int)
+	}
+	total := 0
+	for _, value := range pokemon.evs {
+		total += value
+	}
+	prevMax := maxHP(*pokemon)
+	gained := 0
+	for _, stat := range statNames {
+		amount := min(yield[stat], maxStatEV-pokemon.evs[stat], maxTotalEV-total)
+		if amount <= 0 {
+			continue
+		}
+		pokemon.evs[stat] += amount
+		total += amount
+		gained += amount
+	}
+	if gained > 0 {
+		recalculateStats(pokemon)
+		adjustHPForMaxChange(pokemon, prevMax)
+	}
+	return gained
+}
+
+// migrateLegacyStats upgrades a Pokemon from a save that stored base stats
+// as its stats: it gets rolled IVs and a nature, keeping its damage taken.
+func migrateLegacyStats(r *rand.Rand, pokemon *Pokemon) {
+	if len(pokemon.baseStats) > 0 || len(pokemon.stats) == 0 {
+		return
+	}
+	prevMax := maxHP(*pokemon)
+	pokemon.baseStats = pokemon.stats
+	rollIndividual(r, pokemon)
+	if pokemon.currentHP == prevMax {
+		pokemon.currentHP = maxHP(*pokemon)
+		return
+	}
+	adjustHPForMaxChange(pokemon, prevMax)
+}
+
+// uidRand is a random source seeded by a Pokemon's UID, so what's rolled for
+// it while loading a save comes out the same on every load.
+func uidRand(uid string) *rand.Rand {
+	h := fnv.New64a()
+	h.Write([]byte(uid))
+	return rand.New(rand.NewSource(int64(h.Sum64())))
+}
+
+func natureLabel(natureName string) string {
+	n := natures[natureName]
+	if n.increased == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (+%s, -%s)", n.increased, n.decreased)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"maps"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCalculateStatMatchesReferenceValues(t *testing.T) {
+	// Garchomp, Lv 78, adamant, from the reference stat example.
+	if hp := calculateStat("hp", 108, 24, 74, 78, "adamant"); hp != 289 {
+		t.Fatalf("expected HP 289, got %d", hp)
+	}
+	if attack := calculateStat("attack", 130, 12, 190, 78, "adamant"); attack != 278 {
+		t.Fatalf("expected attack 278, got %d", attack)
+	}
+	if spAttack := calculateStat("special-attack", 80, 16, 48, 78, "adamant"); spAttack != 135 {
+		t.Fatalf("expected special attack 135, got %d", spAttack)
+	}
+}
+
+func TestIndividualsDifferAndLevelRaisesStats(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	base := map[string]int{"hp": 35, "attack": 55, "defense": 40, "special-attack": 50, "special-defense": 50, "speed": 90}
+	first := Pokemon{name: "pikachu", level: 10, baseStats: base}
+	second := Pokemon{name: "pikachu", level: 10, baseStats: base}
+	rollIndividual(r, &first)
+	rollIndividual(r, &second)
+	if first.nature == "" || len(first.ivs) != len(statNames) {
+		t.Fatalf("expected IVs and a nature, got %+v", first)
+	}
+	same := true
+	for _, stat := range statNames {
+		if first.stats[stat] != second.stats[stat] {
+			same = false
+		}
+	}
+	if same {
+		t.Fatalf("expected two individuals to have different stats")
+	}
+
+	speed := first.stats["speed"]
+	setLevel(&first, 50)
+	if first.stats["speed"] <= speed || maxHP(first) != first.stats["hp"] {
+		t.Fatalf("expected stats to grow with level, got %+v", first.stats)
+	}
+}
+
+func TestGainEffortRespectsLimits(t *testing.T) {
+	pokemon := Pokemon{level: 50, baseStats: map[string]int{"hp": 50, "attack": 50}, evs: map[string]int{"attack": 251, "hp": 250}}
+	recalculateStats(&pokemon)
+	if gained := gainEffort(&pokemon, map[string]int{"attack": 3, "hp": 2}); gained != 3 {
+		t.Fatalf("expected 3 EVs gained, got %d", gained)
+	}
+	if pokemon.evs["attack"] != maxStatEV || pokemon.evs["hp"] != 252 {
+		t.Fatalf("unexpected EVs: %+v", pokemon.evs)
+	}
+	pokemon.evs = map[string]int{"attack": 252, "defense": 252, "hp": 5}
+	if gained := gainEffort(&pokemon, map[string]int{"hp": 3}); gained != 1 {
+		t.Fatalf("expected the total cap to allow 1 EV, got %d", gained)
+	}
+}
+
+func TestMigrateLegacyStatsKeepsFullHP(t *testing.T) {
+	pokemon := Pokemon{name: "pidgey", level: 20, stats: map[string]int{"hp": 40, "attack": 45}}
+	pokemon.currentHP = maxHP(pokemon)
+	migrateLegacyStats(rand.New(rand.NewSource(1)), &pokemon)
+	if pokemon.baseStats["hp"] != 40 || pokemon.nature == "" {
+		t.Fatalf("expected base stats and a nature after migrating, got %+v", pokemon)
+	}
+	if pokemon.currentHP != maxHP(pokemon) {
+		t.Fatalf("expected full HP to stay full, got %d/%d", pokemon.currentHP, maxHP(pokemon))
+	}
+}
+
+func TestLegacyStatsMigrateTheSameOnEveryLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trainer.json")
+	legacy := `{"pokedex":{"pidgey":[{"uid":"p1","name":"pidgey","level":20,"stats":{"hp":40,"attack":45,"defense":40,"speed":56}}]}}`
+	if err := os.WriteFile(path, []byte(legacy), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first, err := loadUserData(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := loadUserData(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	a, b := first.Pokedex["pidgey"][0], second.Pokedex["pidgey"][0]
+	if a.nature == "" || a.nature != b.nature || !maps.Equal(a.ivs, b.ivs) {
+		t.Fatalf("expected the same rolls on every load, got %s %v and %s %v", a.nature, a.ivs, b.nature, b.ivs)
+	}
+}
@@ -0,0 +1,455 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type pvpRecord struct {
+	Wins       int       `json:"wins"`
+	Losses     int       `json:"losses"`
+	Draws      int       `json:"draws"`
+	LastPlayed time.Time `json:"last_played"`
+}
+
+type pvpPlayer struct {
+	name       string
+	side       battleSide
+	controller pvpController
+}
+
+// pvpTurn is a player's hidden choice for the round.
+type pvpTurn struct {
+	battleTurn
+	forfeit bool
+}
+
+// pvpController makes one player's choices, at this terminal or over the
+// network.
+type pvpController interface {
+	chooseSendOut(side, opponent *battleSide) (int, error)
+	chooseTurn(side, opponent *battleSide) (pvpTurn, error)
+}
+
+// pvpBattle is a battle between two trainers. Teams fight at full health and
+// nothing but the result is written back to either save.
+type pvpBattle struct {
+	r       *rand.Rand
+	rec     *battleRecorder
+	players [2]*pvpPlayer
+}
+
+func commandPvP(c *config, name ...string) error {
+	if len(name) == 0 {
+		printPvPRecords(c)
+		return nil
+	}
+	if len(name) > 1 {
+		return errors.New("Command pvp takes a single trainer")
+	}
+	other, err := loadTrainerProfile(c, name[0])
+	if err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	configs := [2]*config{c, other}
+	sides := [2]string{sidePlayer, sideTrainer}
+	var players [2]*pvpPlayer
+	for i, trainer := range configs {
+		team, err := pickPvPTeam(reader, trainer)
+		if err != nil {
+			return err
+		}
+		players[i] = &pvpPlayer{
+			name:       trainer.UserName,
+			side:       newBattleSide(sides[i], trainer.UserName+"'s ", team),
+			controller: terminalController{reader: reader, name: trainer.UserName, shared: true},
+		}
+	}
+
+	seed := time.Now().UnixNano()
+	rec := newBattleRecorder(c, seed)
+	rec.record.Opponent = other.UserName
+	defer func() {
+		if err := rec.finish(c); err != nil {
+			fmt.Printf("Warning: failed to record battle: %v\n", err)
+		}
+		mirror := rec.record.mirrored()
+		if err := appendBattleRecord(battleLogPath(other.StoragePath), &mirror); err != nil {
+			fmt.Printf("Warning: failed to record battle: %v\n", err)
+		}
+	}()
+
+	b := &pvpBattle{r: rand.New(rand.NewSource(seed)), rec: rec, players: players}
+	fmt.Println()
+	rec.say(eventIntro, "%s challenges %s!", c.UserName, other.UserName)
+	winner, err := b.run()
+	if err != nil {
+		return err
+	}
+
+	b.announceResult(winner)
+	now := time.Now()
+	for i, trainer := range configs {
+		recordPvPResult(trainer, configs[1-i].UserName, winner, i, now)
+		if err := saveUserData(trainer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadTrainerProfile loads another trainer's save into its own config so it
+// can be saved back independently of the current trainer.
+func loadTrainerProfile(c *config, name string) (*config, error) {
+	if sanitizeUserName(name) == sanitizeUserName(c.UserName) {
+		return nil, errors.New("You can't battle yourself")
+	}
+	path, err := userDataPath(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("No saved trainer named %s", name)
+		}
+		return nil, err
+	}
+	loaded, err := loadUserData(path)
+	if err != nil {
+		return nil, err
+	}
+	other := &config{
+		pokeapiClient: c.pokeapiClient,
+		Pokedex:       make(map[string][]Pokemon),
+		Bag:           defaultBag(),
+		UserName:      name,
+		StoragePath:   path,
+	}
+	applyLoadedUserData(other, loaded)
+	if err := backfillMoveDetails(other); err != nil {
+		fmt.Printf("Warning: failed to look up %s's saved moves: %v\n", name, err)
+	}
+	if loaded.User != "" {
+		other.UserName = loaded.User
+	}
+	ensureParty(other)
+	return other, nil
+}
+
+func pickPvPTeam(reader *bufio.Reader, c *config) ([]Pokemon, error) {
+	party := partyPokemon(c)
+	if len(party) == 0 {
+		return nil, fmt.Errorf("%s has no Pokemon in their party", c.UserName)
+	}
+	fmt.Printf("\n%s, pick your team (numbers separated by spaces, Enter for the whole party):\n", c.UserName)
+	for i, pokemon := range party {
+		fmt.Printf("%d) %s\n", i+1, pokemonLabel(pokemon))
+	}
+	for {
+		fmt.Print("Team > ")
+		input, _, err := readLine(reader)
+		if err != nil {
+			return nil, err
+		}
+		team, err := parseTeamSelection(input, party)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		clearScreen()
+		return team, nil
+	}
+}
+
+// parseTeamSelection picks party members by number, healed copies so the
+// battle can't change the saved Pokemon.
+func parseTeamSelection(input string, party []Pokemon) ([]Pokemon, error) {
+	fields := strings.Fields(input)
+	if len(fields) == 0 {
+		for i := range party {
+			fields = append(fields, strconv.Itoa(i+1))
+		}
+	}
+	team := make([]Pokemon, 0, len(fields))
+	picked := make(map[int]bool)
+	for _, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 1 || n > len(party) {
+			return nil, fmt.Errorf("Enter numbers between 1 and %d", len(party))
+		}
+		if picked[n] {
+			return nil, fmt.Errorf("%s is already on your team", party[n-1].name)
+		}
+		picked[n] = true
+		pokemon := party[n-1]
+		pokemon.moves = append([]PokemonMove(nil), pokemon.moves...)
+		healPokemon(&pokemon)
+		team = append(team, pokemon)
+	}
+	return team, nil
+}
+
+func (b *pvpBattle) run() (int, error) {
+	for i, player := range b.players {
+		index, err := player.controller.chooseSendOut(&player.side, &b.players[1-i].side)
+		if err != nil {
+			return -1, err
+		}
+		player.side.bringIn(index)
+	}
+	for _, player := range b.players {
+		b.announce(player)
+	}
+
+	for round := 1; ; round++ {
+		b.rec.round = round
+		b.printStatus(round)
+
+		var turns [2]pvpTurn
+		for i, player := range b.players {
+			turn, err := player.controller.chooseTurn(&player.side, &b.players[1-i].side)
+			if err != nil {
+				return -1, err
+			}
+			turns[i] = turn
+		}
+		for i, turn := range turns {
+			if turn.forfeit {
+				b.rec.action(b.players[i].side.name, actionRun, "forfeit")
+				b.rec.say(eventRun, "%s forfeited!", b.players[i].name)
+				return 1 - i, nil
+			}
+		}
+
+		for i, turn := range turns {
+			if turn.switchTo >= 0 {
+				player := b.players[i]
+				b.rec.action(player.side.name, actionSwitch, player.side.team[turn.switchTo].pokemon.name)
+				b.rec.say(eventSwitch, "%s withdrew %s!", player.name, player.side.current().pokemon.name)
+				player.side.bringIn(turn.switchTo)
+				b.announce(player)
+			}
+		}
+		for _, i := range b.attackOrder(turns) {
+			attacker, defender := b.players[i].side.current(), b.players[1-i].side.current()
+			if attacker.current <= 0 || defender.current <= 0 {
+				continue
+			}
+			b.rec.action(b.players[i].side.name, actionFight, turns[i].move.name)
+			b.rec.emit(resolveAttack(b.r, attacker, defender, turns[i].move)...)
+		}
+		for _, player := range b.players {
+			b.rec.emit(endOfTurn(b.r, player.side.current())...)
+		}
+
+		for _, player := range b.players {
+			if active := player.side.current(); active.current <= 0 {
+				b.rec.say(eventFaint, "%s fainted!", active.displayName())
+			}
+		}
+		left := [2]int{b.players[0].side.remaining(), b.players[1].side.remaining()}
+		switch {
+		case left[0] == 0 && left[1] == 0:
+			return -1, nil
+		case left[0] == 0:
+			return 1, nil
+		case left[1] == 0:
+			return 0, nil
+		}
+		for i, player := range b.players {
+			if player.side.current().current > 0 {
+				continue
+			}
+			index, err := player.controller.chooseSendOut(&player.side, &b.players[1-i].side)
+			if err != nil {
+				return -1, err
+			}
+			player.side.bringIn(index)
+			b.announce(player)
+		}
+	}
+}
+
+func (b *pvpBattle) attackOrder(turns [2]pvpTurn) []int {
+	switch {
+	case turns[0].switchTo >= 0 && turns[1].switchTo >= 0:
+		return nil
+	case turns[0].switchTo >= 0:
+		return []int{1}
+	case turns[1].switchTo >= 0:
+		return []int{0}
+	case decideFirst(b.r, turns[0].move, turns[1].move, *b.players[0].side.current(), *b.players[1].side.current()):
+		return []int{0, 1}
+	}
+	return []int{1, 0}
+}
+
+func (b *pvpBattle) printStatus(round int) {
+	fmt.Printf("\nRound %d\n", round)
+	for _, player := range b.players {
+		active := player.side.current()
+		fmt.Printf("%s HP: %d/%d%s (%d/%d able to battle)\n", active.displayName(), active.current, active.max, formatStages(active.stages), player.side.remaining(), len(player.side.team))
+	}
+}
+
+func (b *pvpBattle) announce(player *pvpPlayer) {
+	member := player.side.current()
+	if !member.entered {
+		member.entered = true
+		b.rec.addParticipant(player.side.name, member.pokemon)
+	}
+	b.rec.emit(battleEvent{
+		Kind:    eventSwitch,
+		Side:    player.side.name,
+		Pokemon: member.pokemon.name,
+		HP:      member.current,
+		Message: fmt.Sprintf("%s sent out %s!", player.name, member.pokemon.name),
+	})
+}
+
+func (b *pvpBattle) announceResult(winner int) {
+	switch winner {
+	case -1:
+		b.rec.result = resultDraw
+		b.rec.say(eventVictory, "The battle ended in a draw!")
+	case 0:
+		b.rec.result = resultWin
+		b.rec.say(eventVictory, "%s wins!", b.players[0].name)
+	default:
+		b.rec.result = resultLoss
+		b.rec.say(eventVictory, "%s wins!", b.players[1].name)
+	}
+}
+
+// terminalController reads a player's choices from the keyboard. On a
+// shared keyboard it waits for the player to take over and clears the
+// screen afterwards so the other player doesn't see the choice.
+type terminalController struct {
+	reader *bufio.Reader
+	name   string
+	shared bool
+}
+
+func (t terminalController) handoff() error {
+	if !t.shared {
+		return nil
+	}
+	fmt.Printf("\n%s, take the keyboard and press Enter.", t.name)
+	_, _, err := readLine(t.reader)
+	return err
+}
+
+func (t terminalController) done() {
+	if t.shared {
+		clearScreen()
+	}
+}
+
+func (t terminalController) chooseSendOut(side, opponent *battleSide) (int, error) {
+	if err := t.handoff(); err != nil {
+		return 0, err
+	}
+	defer t.done()
+	fmt.Println("Choose a Pokemon to send out.")
+	return t.chooseMember(side, true)
+}
+
+func (t terminalController) chooseTurn(side, opponent *battleSide) (pvpTurn, error) {
+	if err := t.handoff(); err != nil {
+		return pvpTurn{}, err
+	}
+	defer t.done()
+	for {
+		active := side.current()
+		fmt.Printf("\nYour %s HP: %d/%d%s\n", active.pokemon.name, active.current, active.max, formatStages(active.stages))
+		if foe := opponent.current(); foe != nil {
+			fmt.Printf("%s HP: %d/%d%s\n", foe.displayName(), foe.current, foe.max, formatStages(foe.stages))
+		}
+		action, cancelled, err := promptChoice(t.reader, "Choose action: 1) Fight 2) Switch 3) Forfeit > ", 3)
+		if err != nil {
+			return pvpTurn{}, err
+		}
+		if cancelled {
+			continue
+		}
+		switch action {
+		case 1:
+			return pvpTurn{battleTurn: battleTurn{move: chooseMove(t.reader, active.pokemon), switchTo: -1}}, nil
+		case 2:
+			if side.remaining() <= 1 {
+				fmt.Println("No other Pokemon can battle!")
+				continue
+			}
+			index, err := t.chooseMember(side, false)
+			if errors.Is(err, errSelectionCancelled) {
+				continue
+			}
+			if err != nil {
+				return pvpTurn{}, err
+			}
+			return pvpTurn{battleTurn: battleTurn{switchTo: index}}, nil
+		case 3:
+			return pvpTurn{battleTurn: battleTurn{switchTo: -1}, forfeit: true}, nil
+		}
+	}
+}
+
+// chooseMember asks for a team member to send out; forced choices keep
+// asking instead of accepting a cancel.
+func (t terminalController) chooseMember(side *battleSide, forced bool) (int, error) {
+	for {
+		index, err := choosePlayerPokemon(t.reader, side)
+		if errors.Is(err, errSelectionCancelled) && forced {
+			continue
+		}
+		return index, err
+	}
+}
+
+func clearScreen() {
+	fmt.Print("\033[H\033[2J")
+}
+
+func recordPvPResult(c *config, opponent string, winner, side int, at time.Time) {
+	if c.PvP == nil {
+		c.PvP = make(map[string]pvpRecord)
+	}
+	record := c.PvP[opponent]
+	switch winner {
+	case -1:
+		record.Draws++
+	case side:
+		record.Wins++
+	default:
+		record.Losses++
+	}
+	record.LastPlayed = at
+	c.PvP[opponent] = record
+}
+
+func printPvPRecords(c *config) {
+	fmt.Println()
+	fmt.Println("PvP record:")
+	if len(c.PvP) == 0 {
+		fmt.Println("-no battles yet, challenge a trainer with pvp <trainer>")
+	}
+	opponents := make([]string, 0, len(c.PvP))
+	for opponent := range c.PvP {
+		opponents = append(opponents, opponent)
+	}
+	sort.Strings(opponents)
+	for _, opponent := range opponents {
+		record := c.PvP[opponent]
+		fmt.Printf("-vs %s: %d wins, %d losses, %d draws\n", opponent, record.Wins, record.Losses, record.Draws)
+	}
+	fmt.Println()
+}
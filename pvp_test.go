@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTeamSelectionHealsCopies(t *testing.T) {
+	party := []Pokemon{
+		{name: "pikachu", level: 10, currentHP: 1, moves: []PokemonMove{{name: "thunder-shock", pp: 0, maxPP: 30}}},
+		{name: "pidgey", level: 8, currentHP: 20},
+	}
+	team, err := parseTeamSelection("2 1", party)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(team) != 2 || team[0].name != "pidgey" {
+		t.Fatalf("unexpected team: %+v", team)
+	}
+	if team[1].currentHP != maxHP(team[1]) || team[1].moves[0].pp != 30 {
+		t.Fatalf("expected a healed copy, got %+v", team[1])
+	}
+	if party[0].currentHP != 1 || party[0].moves[0].pp != 0 {
+		t.Fatalf("expected the party Pokemon to be untouched, got %+v", party[0])
+	}
+	if _, err := parseTeamSelection("1 1", party); err == nil {
+		t.Fatalf("expected an error for a duplicate pick")
+	}
+}
+
+func TestLoadTrainerProfileAndRecordResult(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	path, err := userDataPath("misty")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	misty := &config{UserName: "misty", StoragePath: path, Bag: defaultBag(), Pokedex: map[string][]Pokemon{
+		"staryu": {{uid: "a1", name: "staryu", level: 18}},
+	}}
+	if err := saveUserData(misty); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ash := &config{UserName: "ash"}
+	if _, err := loadTrainerProfile(ash, "ash"); err == nil {
+		t.Fatalf("expected an error when battling yourself")
+	}
+	other, err := loadTrainerProfile(ash, "misty")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(other.Party) != 1 || other.Party[0] != "a1" {
+		t.Fatalf("expected misty's party to be filled, got %v", other.Party)
+	}
+
+	recordPvPResult(other, "ash", 1, 1, time.Now())
+	recordPvPResult(ash, "misty", 1, 0, time.Now())
+	if other.PvP["ash"].Wins != 1 || ash.PvP["misty"].Losses != 1 {
+		t.Fatalf("unexpected records: %+v %+v", other.PvP, ash.PvP)
+	}
+}
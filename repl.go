@@ -99,7 +99,7 @@ func getCommands() map[string]cliCommand {
 		},
 		"map": {
 			name:        "map",
-			description: "Get the next page of locations (number to explore, arrows to page)",
+			description: "Get the next page of locations (number to explore, arrows to page), or browse a region (map <region>)",
 			callback:    commandMap,
 		},
 		"mapb": {
@@ -109,42 +109,195 @@ func getCommands() map[string]cliCommand {
 		},
 		"explore": {
 			name:        "explore",
-			description: "Get Pokemon located in the specified area (enter a number to battle)",
+			description: "Get Pokemon in an area of your current location (explore [area] [rates], enter a number to battle)",
 			callback:    commandExplore,
 		},
+		"ai": {
+			name:        "ai",
+			description: "List battle AI strategies or compare them (ai harness <battles> <pokemon>...)",
+			callback:    commandAI,
+		},
+		"bag": {
+			name:        "bag",
+			description: "Show your items, use one (bag use <item> <pokemon> [number]) or take a held item back (bag take <pokemon> [number])",
+			callback:    commandBag,
+		},
+		"badges": {
+			name:        "badges",
+			description: "Show your badges, level cap and Hall of Fame",
+			callback:    commandBadges,
+		},
 		"battle": {
 			name:        "battle",
-			description: "Battle a wild Pokemon",
+			description: "Battle a wild Pokemon found in your current area",
 			callback:    commandBattle,
 		},
+		"challenge": {
+			name:        "challenge",
+			description: "Challenge an NPC trainer (list trainers without a name)",
+			callback:    commandChallenge,
+		},
+		"evolve": {
+			name:        "evolve",
+			description: "Evolve a Pokemon that is ready, using items from your bag if needed (evolve <pokemon> [number])",
+			callback:    commandEvolve,
+		},
+		"fish": {
+			name:        "fish",
+			description: "Fish in the current area with a rod from your bag (fish [old|good|super])",
+			callback:    commandFish,
+		},
+		"gym": {
+			name:        "gym",
+			description: "Challenge a gym leader (list gyms without a location)",
+			callback:    commandGym,
+		},
+		"heal": {
+			name:        "heal",
+			description: "Restore your Pokemon at the Pokemon Center (heal cooldown <duration|off>)",
+			callback:    commandHeal,
+		},
+		"headbutt": {
+			name:        "headbutt",
+			description: "Headbutt trees in the current area for wild Pokemon (needs a badge)",
+			callback:    commandHeadbutt,
+		},
+		"host": {
+			name:        "host",
+			description: "Host a PvP battle over the network (host [port])",
+			callback:    commandHost,
+		},
 		"inspect": {
 			name:        "inspect",
 			description: "Inspect a Pokemon you have caught before",
 			callback:    commandInspect,
 		},
+		"join": {
+			name:        "join",
+			description: "Join a networked PvP battle (join <host:port>)",
+			callback:    commandJoin,
+		},
+		"league": {
+			name:        "league",
+			description: "Take on the Elite Four and the Champion",
+			callback:    commandLeague,
+		},
+		"moves": {
+			name:        "moves",
+			description: "View or reorder a Pokemon's moves (moves <pokemon> [number] [swap <slot> <slot>])",
+			callback:    commandMoves,
+		},
+		"party": {
+			name:        "party",
+			description: "Show or manage your party (party add/remove/swap)",
+			callback:    commandParty,
+		},
 		"pokedex": {
 			name:        "pokedex",
 			description: "Show your Pokedex",
 			callback:    commandPokedex,
 		},
+		"pvp": {
+			name:        "pvp",
+			description: "Battle another saved trainer at this keyboard (pvp <trainer>)",
+			callback:    commandPvP,
+		},
+		"regions": {
+			name:        "regions",
+			description: "List regions, then browse their locations and areas",
+			callback:    commandRegions,
+		},
+		"replays": {
+			name:        "replays",
+			description: "List recorded battles",
+			callback:    commandReplays,
+		},
+		"replay": {
+			name:        "replay",
+			description: "Replay a recorded battle (replay <id> [speed|export])",
+			callback:    commandReplay,
+		},
+		"route": {
+			name:        "route",
+			description: "Show the shortest way from your location to another (route to <location>)",
+			callback:    commandRoute,
+		},
+		"shiny": {
+			name:        "shiny",
+			description: "Show or set the shiny odds and your encounter chain (shiny [odds <n>])",
+			callback:    commandShiny,
+		},
+		"simulate": {
+			name:        "simulate",
+			description: "Simulate many battles between two Pokemon (simulate <a> <b> [--level N] [--runs N])",
+			callback:    commandSimulate,
+		},
+		"smash": {
+			name:        "smash",
+			description: "Smash rocks in the current area with Rock Smash (needs a badge)",
+			callback:    commandSmash,
+		},
+		"surf": {
+			name:        "surf",
+			description: "Surf the waters of the current area for wild Pokemon (needs a badge)",
+			callback:    commandSurf,
+		},
+		"travel": {
+			name:        "travel",
+			description: "Show where you are, or travel to a neighboring location (travel [location])",
+			callback:    commandTravel,
+		},
 		"tui": {
 			name:        "tui",
 			description: "Launch the TUI map explorer",
 			callback:    commandTui,
 		},
+		"version": {
+			name:        "version",
+			description: "Show or switch the game version used for encounters, moves and sprites (version [name])",
+			callback:    commandVersion,
+		},
+		"walk": {
+			name:        "walk",
+			description: "Walk through the grass of an area until a wild Pokemon appears (walk [area])",
+			callback:    commandWalk,
+		},
 	}
 }
 
 type config struct {
-	pokeapiClient  pokeapi.Client
-	Next           *string
-	Previous       *string
-	mapFetched     bool
-	Pokedex        map[string][]Pokemon
-	Inventory      Inventory
-	UserName       string
-	StoragePath    string
-	LastDailyGrant string
+	pokeapiClient    pokeapi.Client
+	Next             *string
+	Previous         *string
+	mapFetched       bool
+	Pokedex          map[string][]Pokemon
+	Bag              Bag
+	UserName         string
+	StoragePath      string
+	LastDailyGrant   string
+	Party            []string
+	LastHealAt       time.Time
+	HealCooldown     time.Duration
+	Money            int
+	DefeatedTrainers map[string]time.Time
+	Badges           map[string]time.Time
+	HallOfFame       []hallOfFameEntry
+	PvP              map[string]pvpRecord
+	ShinyOdds        int
+	EncounterChain   encounterChain
+	Version          string
+	// Location is where the trainer stands, and Area the part of it they
+	// last explored.
+	Location string
+	Area     string
+	// forgetMove picks the move to replace when a Pokemon with four moves
+	// learns another; nil prompts on the terminal.
+	forgetMove func(pokemon Pokemon, move PokemonMove) (int, error)
+	// chooseEvolution picks one of several possible evolutions, or -1 to
+	// stop evolving; nil prompts on the terminal.
+	chooseEvolution func(pokemon Pokemon, options []evolutionOption) (int, error)
+	// encounters holds the wild level ranges of the current area.
+	encounters map[string]levelRange
 }
 
 type pokemonAbility struct {
@@ -159,21 +312,34 @@ type PokemonMove struct {
 	accuracy int
 	moveType string
 	priority int
-}
+	pp       int
+	maxPP    int
 
-type Inventory struct {
-	Pokeball  int
-	GreatBall int
-	UltraBall int
-	Potion    int
+	damageClass string
+	target      string
+	statChance  int
+	statChanges []moveStatChange
+	// category is PokeAPI's move meta category, e.g. "damage+raise" for
+	// damaging moves whose stat changes land on the user.
+	category string
 }
 
 type Pokemon struct {
+	uid            string
 	name           string
 	dateCaught     time.Time
 	height         int
 	weight         int
 	stats          map[string]int
+	baseStats      map[string]int
+	ivs            map[string]int
+	evs            map[string]int
+	effortYield    map[string]int
+	nature         string
+	friendship     int
+	shiny          bool
+	gender         string
+	form           string
 	types          []string
 	id             int
 	baseExperience int
@@ -191,4 +357,11 @@ type Pokemon struct {
 	evolutionChain string
 	lastXPAt       time.Time
 	lastXPGain     int
+	currentHP      int
+	fainted        bool
+	// status is a major status like "poison" that lasts between battles,
+	// or empty.
+	status string
+	// heldItem is the bag item the player gave the Pokemon to hold.
+	heldItem string
 }
@@ -10,10 +10,13 @@ func grantRandomSupplies(c *config, reason string) {
 		return
 	}
 	red, blue, black := randomBallReward()
-	c.Inventory.Pokeball += red
-	c.Inventory.GreatBall += blue
-	c.Inventory.UltraBall += black
-	c.Inventory.Potion += 3
+	if c.Bag == nil {
+		c.Bag = make(Bag)
+	}
+	c.Bag.add("poke-ball", red)
+	c.Bag.add("great-ball", blue)
+	c.Bag.add("ultra-ball", black)
+	c.Bag.add("potion", 3)
 	label := strings.TrimSpace(reason)
 	if label == "" {
 		label = "reward"
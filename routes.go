@@ -0,0 +1,112 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+//go:embed data/routes.json
+var routesJSON []byte
+
+type routeMap struct {
+	Regions []routeRegion `json:"regions"`
+}
+
+// routeRegion lists the pairs of PokeAPI locations in a region that are
+// directly connected. Connections go both ways.
+type routeRegion struct {
+	Name   string      `json:"name"`
+	Routes [][2]string `json:"routes"`
+}
+
+// routeGraph maps each location to the locations next to it, in name order.
+type routeGraph map[string][]string
+
+func loadRouteGraph() (routeGraph, error) {
+	return parseRouteGraph(routesJSON)
+}
+
+func parseRouteGraph(data []byte) (routeGraph, error) {
+	var routes routeMap
+	if err := json.Unmarshal(data, &routes); err != nil {
+		return nil, err
+	}
+	graph := make(routeGraph)
+	for _, region := range routes.Regions {
+		for _, route := range region.Routes {
+			from, to := route[0], route[1]
+			if from == "" || to == "" || from == to {
+				return nil, fmt.Errorf("route %s to %s in %s must join two different locations", from, to, region.Name)
+			}
+			for _, pair := range [][2]string{{from, to}, {to, from}} {
+				if !slices.Contains(graph[pair[0]], pair[1]) {
+					graph[pair[0]] = append(graph[pair[0]], pair[1])
+				}
+			}
+		}
+	}
+	for _, next := range graph {
+		slices.Sort(next)
+	}
+	return graph, nil
+}
+
+func (g routeGraph) adjacent(from, to string) bool {
+	return slices.Contains(g[from], to)
+}
+
+// shortestRoute finds the fewest stops from one location to another,
+// including both ends.
+func (g routeGraph) shortestRoute(from, to string) ([]string, bool) {
+	if from == to {
+		return []string{from}, true
+	}
+	previous := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range g[current] {
+			if _, seen := previous[next]; seen {
+				continue
+			}
+			previous[next] = current
+			if next == to {
+				path := []string{to}
+				for step := current; step != ""; step = previous[step] {
+					path = append(path, step)
+				}
+				slices.Reverse(path)
+				return path, true
+			}
+			queue = append(queue, next)
+		}
+	}
+	return nil, false
+}
+
+// checkTravel allows a trip to a location next to the current one.
+func (g routeGraph) checkTravel(from, to string) error {
+	if from == to {
+		return fmt.Errorf("You're already in %s", from)
+	}
+	if !g.adjacent(from, to) {
+		return fmt.Errorf("%s isn't next to %s, enter route to %s for the way there", to, from, to)
+	}
+	return nil
+}
+
+// startLocation is where trainers without a saved position stand: the
+// starting town of their version's region.
+func startLocation(c *config) string {
+	return regionStarts[versionRegion(c)]
+}
+
+func currentLocation(c *config) string {
+	if c == nil || c.Location == "" {
+		return startLocation(c)
+	}
+	return c.Location
+}
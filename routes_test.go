@@ -0,0 +1,131 @@
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestEmbeddedRouteGraph(t *testing.T) {
+	graph, err := loadRouteGraph()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := loadCampaign(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	start := startLocation(nil)
+	for _, g := range data.Gyms {
+		if _, found := graph.shortestRoute(start, g.Location); !found {
+			t.Fatalf("expected a route from %s to the gym in %s", start, g.Location)
+		}
+	}
+	if _, found := graph.shortestRoute(start, data.League.Location); !found {
+		t.Fatalf("expected a route to the league at %s", data.League.Location)
+	}
+	for _, version := range versionOrder {
+		c := &config{Version: version}
+		if start := startLocation(c); len(graph[start]) == 0 {
+			t.Fatalf("expected %s to start on the route map, got %q", version, start)
+		}
+	}
+	for from, next := range graph {
+		for _, to := range next {
+			if !graph.adjacent(to, from) {
+				t.Fatalf("expected %s and %s to connect both ways", from, to)
+			}
+		}
+	}
+}
+
+func TestShortestRouteAndTravelChecks(t *testing.T) {
+	graph, err := parseRouteGraph([]byte(`{"regions":[{"name":"test","routes":[
+		["town","route-1"],["route-1","city"],["town","sea"],["sea","island"],["island","city"],["town","route-1"]
+	]}]}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if next := graph["town"]; !slices.Equal(next, []string{"route-1", "sea"}) {
+		t.Fatalf("expected sorted neighbors without duplicates, got %v", next)
+	}
+	path, found := graph.shortestRoute("town", "city")
+	if !found || !slices.Equal(path, []string{"town", "route-1", "city"}) {
+		t.Fatalf("expected the short way through route-1, got %v", path)
+	}
+	if _, found := graph.shortestRoute("town", "nowhere"); found {
+		t.Fatalf("expected no route to an unmapped location")
+	}
+
+	if err := graph.checkTravel("town", "route-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := graph.checkTravel("town", "city"); err == nil {
+		t.Fatalf("expected travel to a location that isn't adjacent to fail")
+	}
+	if err := graph.checkTravel("town", "town"); err == nil {
+		t.Fatalf("expected travel to the current location to fail")
+	}
+	if err := graph.checkTravel("nowhere", "city"); err == nil {
+		t.Fatalf("expected travel from an unmapped location to fail")
+	}
+
+	if _, err := parseRouteGraph([]byte(`{"regions":[{"name":"test","routes":[["town","town"]]}]}`)); err == nil {
+		t.Fatalf("expected a route to itself to be rejected")
+	}
+}
+
+func TestLocationPersistsAndGatesBattles(t *testing.T) {
+	c := &config{StoragePath: filepath.Join(t.TempDir(), "trainer.json")}
+	if currentLocation(c) != "pallet-town" {
+		t.Fatalf("expected new trainers to start in pallet-town, got %s", currentLocation(c))
+	}
+	if err := commandBattle(c, "pidgey"); err == nil {
+		t.Fatalf("expected battles to need an explored area")
+	}
+	c.Location, c.Area = "viridian-forest", "viridian-forest-area"
+	c.encounters = map[string]levelRange{"caterpie": {min: 3, max: 5}}
+	if err := commandBattle(c, "pidgey"); err == nil {
+		t.Fatalf("expected no pidgey in the forest")
+	}
+	if err := saveUserData(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	loaded, err := loadUserData(c.StoragePath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loaded.Location != "viridian-forest" || loaded.Area != "viridian-forest-area" {
+		t.Fatalf("expected the position to be saved, got %q %q", loaded.Location, loaded.Area)
+	}
+}
+
+func TestVersionRegionPicksStartLocation(t *testing.T) {
+	c := &config{StoragePath: filepath.Join(t.TempDir(), "trainer.json")}
+	c.Location, c.Area = "viridian-forest", "viridian-forest-area"
+	if err := commandVersion(c, "firered"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.Location != "viridian-forest" {
+		t.Fatalf("expected a version in the same region to keep the position, got %q", c.Location)
+	}
+	if err := commandVersion(c, "gold"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if currentLocation(c) != "new-bark-town" || c.Area != "" {
+		t.Fatalf("expected a johto version to start in new-bark-town, got %q %q", currentLocation(c), c.Area)
+	}
+
+	c.Location, c.Area = "somewhere-unmapped", "somewhere-unmapped-area"
+	if err := saveUserData(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	loaded, err := loadUserData(c.StoragePath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	applyLoadedUserData(c, loaded)
+	if currentLocation(c) != "new-bark-town" || c.Area != "" {
+		t.Fatalf("expected a save off the route map to return to new-bark-town, got %q %q", currentLocation(c), c.Area)
+	}
+}
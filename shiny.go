@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+)
+
+const (
+	defaultShinyOdds = 4096
+	// chainStepsPerRoll is how many encounters in a row with the same species
+	// earn an extra shiny roll, up to maxChainRolls extra rolls.
+	chainStepsPerRoll = 10
+	maxChainRolls     = 4
+)
+
+// encounterChain counts consecutive wild encounters with one species.
+type encounterChain struct {
+	Species string `json:"species"`
+	Length  int    `json:"length"`
+}
+
+func (e *encounterChain) extend(species string) {
+	if e.Species != species {
+		e.Species = species
+		e.Length = 0
+	}
+	e.Length++
+}
+
+func (e encounterChain) bonusRolls() int {
+	return min(maxChainRolls, e.Length/chainStepsPerRoll)
+}
+
+func shinyOdds(c *config) int {
+	if c == nil || c.ShinyOdds <= 0 {
+		return defaultShinyOdds
+	}
+	return c.ShinyOdds
+}
+
+// rollShiny rolls once plus once per chain bonus against 1 in odds.
+func rollShiny(r *rand.Rand, odds int, chain encounterChain) bool {
+	for range 1 + chain.bonusRolls() {
+		if r.Intn(odds) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func shinyMarker(pokemon Pokemon) string {
+	if pokemon.shiny {
+		return " (shiny)"
+	}
+	return ""
+}
+
+func commandShiny(c *config, args ...string) error {
+	switch {
+	case len(args) == 0:
+	case len(args) == 2 && args[0] == "odds":
+		odds, err := strconv.Atoi(args[1])
+		if err != nil || odds < 1 {
+			return errors.New("Enter the odds as a positive number, e.g. shiny odds 4096")
+		}
+		c.ShinyOdds = odds
+		if err := saveUserData(c); err != nil {
+			return err
+		}
+	default:
+		return errors.New("Usage: shiny [odds <n>]")
+	}
+
+	odds := shinyOdds(c)
+	fmt.Println()
+	fmt.Printf("Shiny odds: 1 in %d\n", odds)
+	if c.EncounterChain.Length > 0 {
+		rolls := 1 + c.EncounterChain.bonusRolls()
+		fmt.Printf("Encounter chain: %s x%d (%d rolls per encounter)\n", c.EncounterChain.Species, c.EncounterChain.Length, rolls)
+	}
+	return nil
+}
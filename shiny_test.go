@@ -0,0 +1,47 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestEncounterChainBoostsShinyRolls(t *testing.T) {
+	var chain encounterChain
+	for range 25 {
+		chain.extend("pikachu")
+	}
+	if chain.Length != 25 || chain.bonusRolls() != 2 {
+		t.Fatalf("expected a 25 chain with 2 bonus rolls, got %+v", chain)
+	}
+	chain.extend("pidgey")
+	if chain.Species != "pidgey" || chain.Length != 1 || chain.bonusRolls() != 0 {
+		t.Fatalf("expected a new species to restart the chain, got %+v", chain)
+	}
+
+	if !rollShiny(rand.New(rand.NewSource(1)), 1, encounterChain{}) {
+		t.Fatalf("expected 1 in 1 odds to always be shiny")
+	}
+	r := rand.New(rand.NewSource(9))
+	plain, chained := 0, 0
+	for range 20000 {
+		if rollShiny(r, 100, encounterChain{}) {
+			plain++
+		}
+		if rollShiny(r, 100, encounterChain{Species: "pikachu", Length: 40}) {
+			chained++
+		}
+	}
+	if chained <= plain*3 {
+		t.Fatalf("expected a long chain to be much likelier shiny, got %d vs %d", chained, plain)
+	}
+}
+
+func TestShinyFlagRoundTrips(t *testing.T) {
+	record := pokemonToRecord(Pokemon{name: "gyarados", level: 30, shiny: true})
+	if !record.Shiny || !recordToPokemon(record).shiny {
+		t.Fatalf("expected the shiny flag to be saved and loaded")
+	}
+	if shinyOdds(&config{}) != defaultShinyOdds || shinyOdds(&config{ShinyOdds: 512}) != 512 {
+		t.Fatalf("unexpected shiny odds")
+	}
+}
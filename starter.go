@@ -49,7 +49,7 @@ func ensureStarterPokemon(c *config, dataExists bool) error {
 		if err != nil {
 			return err
 		}
-		appendCaughtPokemon(c, pokemon)
+		catchIntoPokedex(c, pokemon)
 		if err := saveUserData(c); err != nil {
 			return err
 		}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	minStatStage = -6
+	maxStatStage = 6
+)
+
+var stageStats = []string{"attack", "defense", "special-attack", "special-defense", "speed", "accuracy", "evasion"}
+
+var stageLabels = map[string]string{
+	"attack":          "Atk",
+	"defense":         "Def",
+	"special-attack":  "SpA",
+	"special-defense": "SpD",
+	"speed":           "Spe",
+	"accuracy":        "Acc",
+	"evasion":         "Eva",
+}
+
+type statStages map[string]int
+
+type moveStatChange struct {
+	stat   string
+	change int
+}
+
+func statStageMultiplier(stage int) float64 {
+	stage = clampStage(stage)
+	if stage >= 0 {
+		return float64(2+stage) / 2
+	}
+	return 2 / float64(2-stage)
+}
+
+func accuracyStageMultiplier(stage int) float64 {
+	stage = clampStage(stage)
+	if stage >= 0 {
+		return float64(3+stage) / 3
+	}
+	return 3 / float64(3-stage)
+}
+
+func clampStage(stage int) int {
+	return min(maxStatStage, max(minStatStage, stage))
+}
+
+// effectiveStat applies stat stages; paralysis also halves speed.
+func effectiveStat(pokemon battlePokemon, stat string) int {
+	value := float64(pokemon.pokemon.stats[stat]) * statStageMultiplier(pokemon.stages[stat])
+	if stat == "speed" && pokemon.status == statusParalysis {
+		value /= 2
+	}
+	return int(value)
+}
+
+func applyStatChange(target *battlePokemon, stat string, change int) battleEvent {
+	if target.stages == nil {
+		target.stages = make(statStages)
+	}
+	event := battleEvent{
+		Kind:    eventStatChange,
+		Side:    target.side,
+		Pokemon: target.pokemon.name,
+		HP:      target.current,
+	}
+	current := target.stages[stat]
+	next := clampStage(current + change)
+	label := strings.ReplaceAll(stat, "-", " ")
+	if next == current {
+		if change > 0 {
+			event.Message = fmt.Sprintf("%s's %s won't go any higher!", target.displayName(), label)
+		} else {
+			event.Message = fmt.Sprintf("%s's %s won't go any lower!", target.displayName(), label)
+		}
+		return event
+	}
+	target.stages[stat] = next
+	event.Message = fmt.Sprintf("%s's %s %s!", target.displayName(), label, stageChangeVerb(next-current))
+	return event
+}
+
+func stageChangeVerb(delta int) string {
+	switch {
+	case delta >= 3:
+		return "rose drastically"
+	case delta == 2:
+		return "sharply rose"
+	case delta == 1:
+		return "rose"
+	case delta == -1:
+		return "fell"
+	case delta == -2:
+		return "harshly fell"
+	default:
+		return "severely fell"
+	}
+}
+
+func formatStages(stages statStages) string {
+	parts := make([]string, 0, len(stageStats))
+	for _, stat := range stageStats {
+		if stage := stages[stat]; stage != 0 {
+			parts = append(parts, fmt.Sprintf("%s %+d", stageLabels[stat], stage))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(parts, " ") + "]"
+}
+
+func isStatusMove(move PokemonMove) bool {
+	return move.damageClass == "status"
+}
+
+// statChangesHitUser reports whether a move's stat changes apply to the
+// user: status moves aimed at the user, and damaging moves like overheat
+// or close-combat that PokeAPI files under "damage+raise".
+func statChangesHitUser(move PokemonMove) bool {
+	if isStatusMove(move) {
+		return moveTargetsUser(move)
+	}
+	return move.category == "damage+raise"
+}
+
+func moveTargetsUser(move PokemonMove) bool {
+	return move.target == "user" || move.target == "users-field" || move.target == "user-and-allies"
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestStatStageMultiplier(t *testing.T) {
+	cases := map[int]float64{-6: 0.25, -1: 2.0 / 3.0, 0: 1, 2: 2, 6: 4, 9: 4}
+	for stage, want := range cases {
+		if got := statStageMultiplier(stage); math.Abs(got-want) > floatTolerance {
+			t.Fatalf("stage %d: expected %v, got %v", stage, want, got)
+		}
+	}
+}
+
+func TestStatusMoveChangesStagesInsteadOfDamage(t *testing.T) {
+	attacker := newBattlePokemon(Pokemon{name: "scyther", level: 20, stats: map[string]int{"attack": 110}})
+	defender := newBattlePokemon(Pokemon{name: "onix", level: 20, stats: map[string]int{"defense": 160}})
+	swordsDance := PokemonMove{
+		name:        "swords-dance",
+		damageClass: "status",
+		target:      "user",
+		statChanges: []moveStatChange{{stat: "attack", change: 2}},
+	}
+	before := defender.current
+	events := resolveAttack(rand.New(rand.NewSource(1)), &attacker, &defender, swordsDance)
+	if defender.current != before {
+		t.Fatalf("expected no damage from a status move")
+	}
+	if attacker.stages["attack"] != 2 {
+		t.Fatalf("expected attack stage +2, got %d", attacker.stages["attack"])
+	}
+	if len(events) != 2 || events[1].Kind != eventStatChange {
+		t.Fatalf("expected a stat change event, got %+v", events)
+	}
+
+	for range 5 {
+		applyStatChange(&attacker, "attack", 2)
+	}
+	if attacker.stages["attack"] != maxStatStage {
+		t.Fatalf("expected attack stage to cap at %d, got %d", maxStatStage, attacker.stages["attack"])
+	}
+}
+
+func TestStatusMoveWithoutStatChangesDealsNoDamage(t *testing.T) {
+	attacker := newBattlePokemon(Pokemon{name: "pikachu", level: 20, types: []string{"electric"}, stats: map[string]int{"attack": 55}})
+	defender := newBattlePokemon(Pokemon{name: "pidgey", level: 20, types: []string{"normal", "flying"}, stats: map[string]int{"defense": 40}})
+	thunderWave := PokemonMove{name: "thunder-wave", accuracy: 100, moveType: "electric", damageClass: "status", target: "selected-pokemon"}
+	before := defender.current
+	events := resolveAttack(rand.New(rand.NewSource(1)), &attacker, &defender, thunderWave)
+	if defender.current != before || events[0].Damage != 0 {
+		t.Fatalf("expected thunder-wave to deal no damage, HP went %d -> %d", before, defender.current)
+	}
+	if calculateDamage(attacker, defender, thunderWave) != 0 {
+		t.Fatalf("expected no damage from a status move")
+	}
+}
+
+func TestDamagingMoveSelfDropLowersUser(t *testing.T) {
+	attacker := newBattlePokemon(Pokemon{name: "charizard", level: 50, types: []string{"fire", "flying"}, stats: map[string]int{"special-attack": 109}})
+	defender := newBattlePokemon(Pokemon{name: "snorlax", level: 50, types: []string{"normal"}, stats: map[string]int{"hp": 1000, "special-defense": 110}, currentHP: 1100})
+	overheat := PokemonMove{
+		name:        "overheat",
+		power:       130,
+		accuracy:    100,
+		moveType:    "fire",
+		damageClass: "special",
+		target:      "selected-pokemon",
+		statChance:  100,
+		statChanges: []moveStatChange{{stat: "special-attack", change: -2}},
+		category:    "damage+raise",
+	}
+	resolveAttack(rand.New(rand.NewSource(1)), &attacker, &defender, overheat)
+	if attacker.stages["special-attack"] != -2 {
+		t.Fatalf("expected overheat to drop the user's special attack by 2, got %d", attacker.stages["special-attack"])
+	}
+	if defender.stages["special-attack"] != 0 {
+		t.Fatalf("expected the foe's stats to be untouched, got %d", defender.stages["special-attack"])
+	}
+
+	crunch := PokemonMove{
+		name:        "crunch",
+		power:       80,
+		accuracy:    100,
+		moveType:    "dark",
+		damageClass: "physical",
+		statChance:  100,
+		statChanges: []moveStatChange{{stat: "defense", change: -1}},
+		category:    "damage+lower",
+	}
+	resolveAttack(rand.New(rand.NewSource(1)), &attacker, &defender, crunch)
+	if defender.stages["defense"] != -1 || attacker.stages["defense"] != 0 {
+		t.Fatalf("expected crunch to lower the foe's defense, got foe %d user %d", defender.stages["defense"], attacker.stages["defense"])
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// inflictStatus gives p a major status unless it already has one. Sleep
+// lasts one to three of the sleeper's turns.
+func inflictStatus(r *rand.Rand, p *battlePokemon, status, message string) []battleEvent {
+	if p.current <= 0 || p.status != statusNone {
+		return nil
+	}
+	p.status = status
+	if status == statusSleep {
+		p.sleepTurns = 1 + r.Intn(3)
+	}
+	return []battleEvent{{
+		Kind:    eventStatus,
+		Side:    p.side,
+		Pokemon: p.pokemon.name,
+		HP:      p.current,
+		Message: message,
+	}}
+}
+
+// statusBeforeMove checks whether p's status lets it act this turn. stopped
+// is true when the Pokemon is asleep, frozen or fully paralyzed.
+func statusBeforeMove(r *rand.Rand, p *battlePokemon) (events []battleEvent, stopped bool) {
+	name := p.displayName()
+	say := func(message string) []battleEvent {
+		return []battleEvent{{
+			Kind:    eventStatus,
+			Side:    p.side,
+			Pokemon: p.pokemon.name,
+			HP:      p.current,
+			Message: message,
+		}}
+	}
+	switch p.status {
+	case statusSleep:
+		if p.sleepTurns > 0 {
+			p.sleepTurns--
+			return say(fmt.Sprintf("%s is fast asleep.", name)), true
+		}
+		p.status = statusNone
+		return say(fmt.Sprintf("%s woke up!", name)), false
+	case statusFreeze:
+		if r.Intn(5) > 0 {
+			return say(fmt.Sprintf("%s is frozen solid!", name)), true
+		}
+		p.status = statusNone
+		return say(fmt.Sprintf("%s thawed out!", name)), false
+	case statusParalysis:
+		if r.Intn(4) == 0 {
+			return say(fmt.Sprintf("%s is paralyzed! It can't move!", name)), true
+		}
+	}
+	return nil, false
+}
+
+// endOfTurn hurts a burned or poisoned Pokemon and lets its held item act
+// once both sides have moved.
+func endOfTurn(r *rand.Rand, p *battlePokemon) []battleEvent {
+	if p == nil || p.current <= 0 {
+		return nil
+	}
+	var events []battleEvent
+	damage, cause := 0, ""
+	switch p.status {
+	case statusBurn:
+		damage, cause = max(1, p.max/16), "its burn"
+	case statusPoison:
+		damage, cause = max(1, p.max/8), "poison"
+	}
+	if damage > 0 {
+		p.current = max(0, p.current-damage)
+		events = append(events, battleEvent{
+			Kind:    eventStatus,
+			Side:    p.side,
+			Pokemon: p.pokemon.name,
+			Damage:  damage,
+			HP:      p.current,
+			Message: fmt.Sprintf("%s is hurt by %s for %d damage!", p.displayName(), cause, damage),
+		})
+	}
+	if item := lookupItem(p.pokemon.heldItem); item.category == itemCategoryInflict {
+		message := fmt.Sprintf("%s was %s by its %s!", p.displayName(), inflictedVerbs[item.inflicts], itemDisplayName(item.name))
+		events = append(events, inflictStatus(r, p, item.inflicts, message)...)
+	}
+	return events
+}
+
+// inflictedVerbs describe a status being given, as in "was burned".
+var inflictedVerbs = map[string]string{
+	statusBurn:      "burned",
+	statusPoison:    "poisoned",
+	statusParalysis: "paralyzed",
+	statusSleep:     "put to sleep",
+	statusFreeze:    "frozen",
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func statusTestPokemon(status string) battlePokemon {
+	pokemon := Pokemon{name: "pidgey", level: 10, types: []string{"normal"}, stats: map[string]int{"hp": 80, "attack": 40, "defense": 40, "speed": 60}, baseStats: map[string]int{"hp": 40}, status: status}
+	restoreHP(&pokemon)
+	return newBattlePokemon(pokemon)
+}
+
+func TestSleepSkipsTurnsThenWearsOff(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	sleeper, foe := statusTestPokemon(""), statusTestPokemon("")
+	inflictStatus(r, &sleeper, statusSleep, "pidgey fell asleep!")
+	turns := sleeper.sleepTurns
+	if turns < 1 || turns > 3 {
+		t.Fatalf("expected one to three turns of sleep, got %d", turns)
+	}
+	tackle := PokemonMove{name: "tackle", power: 40, accuracy: 100, moveType: "normal", damageClass: "physical"}
+	for i := 0; i < turns; i++ {
+		events := resolveAttack(r, &sleeper, &foe, tackle)
+		if len(events) != 1 || events[0].Kind != eventStatus || foe.current != foe.max {
+			t.Fatalf("expected turn %d to be skipped, got %+v", i+1, events)
+		}
+	}
+	events := resolveAttack(r, &sleeper, &foe, tackle)
+	if sleeper.status != statusNone || len(events) != 2 || events[1].Kind != eventAttack {
+		t.Fatalf("expected the sleeper to wake and attack, got %q and %+v", sleeper.status, events)
+	}
+}
+
+func TestParalysisHalvesSpeedAndCanStopAMove(t *testing.T) {
+	paralyzed := statusTestPokemon(statusParalysis)
+	if got := effectiveStat(paralyzed, "speed"); got != 30 {
+		t.Fatalf("expected paralysis to halve speed to 30, got %d", got)
+	}
+	r := rand.New(rand.NewSource(1))
+	stopped := 0
+	for i := 0; i < 400; i++ {
+		if _, skip := statusBeforeMove(r, &paralyzed); skip {
+			stopped++
+		}
+	}
+	if stopped < 60 || stopped > 140 {
+		t.Fatalf("expected about a quarter of moves to be stopped, got %d of 400", stopped)
+	}
+}
+
+func TestBurnAndPoisonHurtAtTheEndOfTheTurn(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for status, damage := range map[string]int{statusBurn: 5, statusPoison: 10} {
+		p := statusTestPokemon(status)
+		events := endOfTurn(r, &p)
+		if len(events) != 1 || events[0].Damage != damage || p.current != p.max-damage || events[0].HP != p.current {
+			t.Fatalf("%s: expected %d damage, got %+v", status, damage, events)
+		}
+	}
+
+	healthy, burned := statusTestPokemon(""), statusTestPokemon(statusBurn)
+	target := statusTestPokemon("")
+	tackle := PokemonMove{name: "tackle", power: 40, accuracy: 100, moveType: "normal", damageClass: "physical"}
+	if full, halved := calculateDamage(healthy, target, tackle), calculateDamage(burned, target, tackle); halved >= full {
+		t.Fatalf("expected a burn to weaken physical moves, got %d vs %d", halved, full)
+	}
+}
@@ -13,12 +13,47 @@ import (
 )
 
 type userData struct {
-	User           string                     `json:"user"`
-	Pokedex        map[string][]pokemonRecord `json:"pokedex"`
-	Inventory      inventoryRecord            `json:"inventory"`
-	LastDailyGrant string                     `json:"last_daily_grant"`
+	User             string                     `json:"user"`
+	Pokedex          map[string][]pokemonRecord `json:"pokedex"`
+	Bag              map[string]int             `json:"bag"`
+	LastDailyGrant   string                     `json:"last_daily_grant"`
+	Party            []string                   `json:"party"`
+	LastHealAt       time.Time                  `json:"last_heal_at"`
+	HealCooldown     int                        `json:"heal_cooldown_seconds"`
+	Money            int                        `json:"money"`
+	DefeatedTrainers map[string]time.Time       `json:"defeated_trainers"`
+	Badges           map[string]time.Time       `json:"badges"`
+	HallOfFame       []hallOfFameEntry          `json:"hall_of_fame"`
+	PvP              map[string]pvpRecord       `json:"pvp"`
+	ShinyOdds        int                        `json:"shiny_odds,omitempty"`
+	EncounterChain   encounterChain             `json:"encounter_chain"`
+	Version          string                     `json:"version,omitempty"`
+	Location         string                     `json:"location,omitempty"`
+	Area             string                     `json:"area,omitempty"`
 }
 
+type loadedUserData struct {
+	User             string
+	Pokedex          map[string][]Pokemon
+	Bag              Bag
+	LastDailyGrant   string
+	Party            []string
+	LastHealAt       time.Time
+	HealCooldown     time.Duration
+	Money            int
+	DefeatedTrainers map[string]time.Time
+	Badges           map[string]time.Time
+	HallOfFame       []hallOfFameEntry
+	PvP              map[string]pvpRecord
+	ShinyOdds        int
+	EncounterChain   encounterChain
+	Version          string
+	Location         string
+	Area             string
+}
+
+// inventoryRecord is the fixed four-counter inventory used by older saves.
+// It is only read to migrate those counters into the bag.
 type inventoryRecord struct {
 	Pokeball  int `json:"pokeball"`
 	GreatBall int `json:"great_ball"`
@@ -33,11 +68,21 @@ type pokemonAbilityRecord struct {
 }
 
 type pokemonRecord struct {
+	UID            string                 `json:"uid"`
 	Name           string                 `json:"name"`
 	DateCaught     time.Time              `json:"date_caught"`
 	Height         int                    `json:"height"`
 	Weight         int                    `json:"weight"`
 	Stats          map[string]int         `json:"stats"`
+	BaseStats      map[string]int         `json:"base_stats,omitempty"`
+	IVs            map[string]int         `json:"ivs,omitempty"`
+	EVs            map[string]int         `json:"evs,omitempty"`
+	EffortYield    map[string]int         `json:"effort_yield,omitempty"`
+	Nature         string                 `json:"nature,omitempty"`
+	Friendship     *int                   `json:"friendship,omitempty"`
+	Shiny          bool                   `json:"shiny,omitempty"`
+	Gender         string                 `json:"gender,omitempty"`
+	Form           string                 `json:"form,omitempty"`
 	Types          []string               `json:"types"`
 	ID             int                    `json:"id"`
 	BaseExperience int                    `json:"base_experience"`
@@ -55,6 +100,10 @@ type pokemonRecord struct {
 	EvolutionChain string                 `json:"evolution_chain"`
 	LastXPAt       time.Time              `json:"last_xp_at"`
 	LastXPGain     int                    `json:"last_xp_gain"`
+	CurrentHP      int                    `json:"current_hp"`
+	Fainted        bool                   `json:"fainted"`
+	Status         string                 `json:"status,omitempty"`
+	HeldItem       string                 `json:"held_item,omitempty"`
 }
 
 type pokemonMoveRecord struct {
@@ -63,6 +112,19 @@ type pokemonMoveRecord struct {
 	Accuracy int    `json:"accuracy"`
 	Type     string `json:"type"`
 	Priority int    `json:"priority"`
+	PP       int    `json:"pp"`
+	MaxPP    int    `json:"max_pp"`
+
+	DamageClass string                 `json:"damage_class,omitempty"`
+	Target      string                 `json:"target,omitempty"`
+	StatChance  int                    `json:"stat_chance,omitempty"`
+	StatChanges []moveStatChangeRecord `json:"stat_changes,omitempty"`
+	Category    string                 `json:"category,omitempty"`
+}
+
+type moveStatChangeRecord struct {
+	Stat   string `json:"stat"`
+	Change int    `json:"change"`
 }
 
 func promptUserName() (string, error) {
@@ -84,6 +146,29 @@ func promptUserName() (string, error) {
 	return name, nil
 }
 
+// promptVersion asks a new trainer which game version to play, defaulting
+// to red.
+func promptVersion() (string, error) {
+	line := liner.NewLiner()
+	defer line.Close()
+	line.SetCtrlCAborts(true)
+	input, err := line.Prompt("Game version (" + defaultVersion + "): ")
+	if err != nil {
+		if errors.Is(err, liner.ErrPromptAborted) || errors.Is(err, io.EOF) {
+			return defaultVersion, nil
+		}
+		return "", err
+	}
+	if strings.TrimSpace(input) == "" {
+		return defaultVersion, nil
+	}
+	version, known := parseVersion(input)
+	if !known {
+		return defaultVersion, errors.New("unknown version " + version)
+	}
+	return version, nil
+}
+
 func defaultUserName() string {
 	for _, key := range []string{"USER", "LOGNAME", "USERNAME"} {
 		if value := strings.TrimSpace(os.Getenv(key)); value != "" {
@@ -133,26 +218,41 @@ func userDataPath(userName string) (string, error) {
 	return filepath.Join(dataDir, fileName+".json"), nil
 }
 
-func loadUserData(path string) (map[string][]Pokemon, Inventory, string, error) {
+func loadUserData(path string) (loadedUserData, error) {
+	empty := loadedUserData{Pokedex: make(map[string][]Pokemon), Bag: defaultBag()}
 	if path == "" {
-		return make(map[string][]Pokemon), defaultInventory(), "", nil
+		return empty, nil
 	}
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return make(map[string][]Pokemon), defaultInventory(), "", nil
+			return empty, nil
 		}
-		return nil, Inventory{}, "", err
+		return loadedUserData{}, err
 	}
 
 	var raw struct {
-		User           string                     `json:"user"`
-		Pokedex        map[string]json.RawMessage `json:"pokedex"`
-		Inventory      *inventoryRecord           `json:"inventory"`
-		LastDailyGrant string                     `json:"last_daily_grant"`
+		User             string                     `json:"user"`
+		Pokedex          map[string]json.RawMessage `json:"pokedex"`
+		Inventory        *inventoryRecord           `json:"inventory"`
+		Bag              map[string]int             `json:"bag"`
+		LastDailyGrant   string                     `json:"last_daily_grant"`
+		Party            []string                   `json:"party"`
+		LastHealAt       time.Time                  `json:"last_heal_at"`
+		HealCooldown     int                        `json:"heal_cooldown_seconds"`
+		Money            int                        `json:"money"`
+		DefeatedTrainers map[string]time.Time       `json:"defeated_trainers"`
+		Badges           map[string]time.Time       `json:"badges"`
+		HallOfFame       []hallOfFameEntry          `json:"hall_of_fame"`
+		PvP              map[string]pvpRecord       `json:"pvp"`
+		ShinyOdds        int                        `json:"shiny_odds"`
+		EncounterChain   encounterChain             `json:"encounter_chain"`
+		Version          string                     `json:"version"`
+		Location         string                     `json:"location"`
+		Area             string                     `json:"area"`
 	}
 	if err := json.Unmarshal(data, &raw); err != nil {
-		return nil, Inventory{}, "", err
+		return loadedUserData{}, err
 	}
 	result := make(map[string][]Pokemon, len(raw.Pokedex))
 	for name, payload := range raw.Pokedex {
@@ -160,22 +260,122 @@ func loadUserData(path string) (map[string][]Pokemon, Inventory, string, error)
 		if err := json.Unmarshal(payload, &records); err == nil {
 			pokemons := make([]Pokemon, 0, len(records))
 			for _, record := range records {
-				pokemons = append(pokemons, recordToPokemon(record))
+				pokemon := recordToPokemon(record)
+				migrateLegacyStats(uidRand(pokemon.uid), &pokemon)
+				trimMoves(&pokemon)
+				pokemons = append(pokemons, pokemon)
 			}
 			result[name] = pokemons
 			continue
 		}
 		var record pokemonRecord
 		if err := json.Unmarshal(payload, &record); err != nil {
-			return nil, Inventory{}, "", err
+			return loadedUserData{}, err
+		}
+		pokemon := recordToPokemon(record)
+		migrateLegacyStats(uidRand(pokemon.uid), &pokemon)
+		trimMoves(&pokemon)
+		result[name] = []Pokemon{pokemon}
+	}
+	bag := defaultBag()
+	switch {
+	case raw.Bag != nil:
+		bag = Bag(raw.Bag)
+	case raw.Inventory != nil:
+		bag = bagFromInventoryRecord(*raw.Inventory)
+	}
+	return loadedUserData{
+		User:             raw.User,
+		Pokedex:          result,
+		Bag:              bag,
+		LastDailyGrant:   raw.LastDailyGrant,
+		Party:            raw.Party,
+		LastHealAt:       raw.LastHealAt,
+		HealCooldown:     time.Duration(raw.HealCooldown) * time.Second,
+		Money:            raw.Money,
+		DefeatedTrainers: raw.DefeatedTrainers,
+		Badges:           raw.Badges,
+		HallOfFame:       raw.HallOfFame,
+		PvP:              raw.PvP,
+		ShinyOdds:        raw.ShinyOdds,
+		EncounterChain:   raw.EncounterChain,
+		Version:          raw.Version,
+		Location:         raw.Location,
+		Area:             raw.Area,
+	}, nil
+}
+
+func applyLoadedUserData(c *config, loaded loadedUserData) {
+	c.Pokedex = loaded.Pokedex
+	if loaded.Bag != nil {
+		c.Bag = loaded.Bag
+	}
+	c.LastDailyGrant = loaded.LastDailyGrant
+	c.Party = loaded.Party
+	c.LastHealAt = loaded.LastHealAt
+	c.HealCooldown = loaded.HealCooldown
+	c.Money = loaded.Money
+	c.DefeatedTrainers = loaded.DefeatedTrainers
+	c.Badges = loaded.Badges
+	c.HallOfFame = loaded.HallOfFame
+	c.PvP = loaded.PvP
+	c.ShinyOdds = loaded.ShinyOdds
+	c.EncounterChain = loaded.EncounterChain
+	c.Version = loaded.Version
+	c.Location = loaded.Location
+	c.Area = loaded.Area
+	// Older saves could stand anywhere; trainers off the route map go back
+	// to their starting town.
+	if graph, err := loadRouteGraph(); err == nil && len(graph[c.Location]) == 0 {
+		c.Location, c.Area = "", ""
+	}
+}
+
+// backfillMoveDetails looks up the damage class, target, stat changes and
+// category that moves from older saves lack; without them Growl would hit
+// like a 40 power move. When PokeAPI can't be reached, a move without power
+// is still treated as a status move and the first lookup error is returned.
+func backfillMoveDetails(c *config) error {
+	details := make(map[string]PokemonMove)
+	var lookupErr error
+	for _, pokes := range c.Pokedex {
+		for i := range pokes {
+			for j := range pokes[i].moves {
+				move := &pokes[i].moves[j]
+				if move.damageClass != "" {
+					continue
+				}
+				detail, found := details[move.name]
+				if !found {
+					moveResp, err := c.pokeapiClient.GetMoveByName(move.name)
+					if err != nil {
+						if lookupErr == nil {
+							lookupErr = err
+						}
+						detail = legacyMoveDetails(*move)
+					} else {
+						detail = buildMove(moveResp)
+					}
+					details[move.name] = detail
+				}
+				move.damageClass = detail.damageClass
+				move.target = detail.target
+				move.statChance = detail.statChance
+				move.statChanges = detail.statChanges
+				move.category = detail.category
+			}
 		}
-		result[name] = []Pokemon{recordToPokemon(record)}
 	}
-	inv := defaultInventory()
-	if raw.Inventory != nil {
-		inv = inventoryFromRecord(*raw.Inventory)
+	return lookupErr
+}
+
+// legacyMoveDetails guesses a saved move's damage class from its power.
+// Return and Frustration have no listed power but still deal damage.
+func legacyMoveDetails(move PokemonMove) PokemonMove {
+	if _, friendship := friendshipMovePower(Pokemon{}, move); move.power <= 0 && !friendship {
+		move.damageClass = "status"
 	}
-	return result, inv, raw.LastDailyGrant, nil
+	return move
 }
 
 func saveUserData(c *config) error {
@@ -191,10 +391,23 @@ func saveUserData(c *config) error {
 		records[name] = entries
 	}
 	payload := userData{
-		User:           c.UserName,
-		Pokedex:        records,
-		Inventory:      inventoryToRecord(c.Inventory),
-		LastDailyGrant: c.LastDailyGrant,
+		User:             c.UserName,
+		Pokedex:          records,
+		Bag:              c.Bag,
+		LastDailyGrant:   c.LastDailyGrant,
+		Party:            append([]string(nil), c.Party...),
+		LastHealAt:       c.LastHealAt,
+		HealCooldown:     int(c.HealCooldown / time.Second),
+		Money:            c.Money,
+		DefeatedTrainers: c.DefeatedTrainers,
+		Badges:           c.Badges,
+		HallOfFame:       c.HallOfFame,
+		PvP:              c.PvP,
+		ShinyOdds:        c.ShinyOdds,
+		EncounterChain:   c.EncounterChain,
+		Version:          c.Version,
+		Location:         c.Location,
+		Area:             c.Area,
 	}
 	data, err := json.MarshalIndent(payload, "", "  ")
 	if err != nil {
@@ -203,31 +416,13 @@ func saveUserData(c *config) error {
 	return os.WriteFile(c.StoragePath, data, 0o600)
 }
 
-func defaultInventory() Inventory {
-	return Inventory{
-		Pokeball:  10,
-		GreatBall: 5,
-		UltraBall: 2,
-		Potion:    3,
-	}
-}
-
-func inventoryFromRecord(record inventoryRecord) Inventory {
-	return Inventory{
-		Pokeball:  record.Pokeball,
-		GreatBall: record.GreatBall,
-		UltraBall: record.UltraBall,
-		Potion:    record.Potion,
-	}
-}
-
-func inventoryToRecord(inv Inventory) inventoryRecord {
-	return inventoryRecord{
-		Pokeball:  inv.Pokeball,
-		GreatBall: inv.GreatBall,
-		UltraBall: inv.UltraBall,
-		Potion:    inv.Potion,
-	}
+func bagFromInventoryRecord(record inventoryRecord) Bag {
+	bag := make(Bag)
+	bag.add("poke-ball", record.Pokeball)
+	bag.add("great-ball", record.GreatBall)
+	bag.add("ultra-ball", record.UltraBall)
+	bag.add("potion", record.Potion)
+	return bag
 }
 
 func pokemonToRecord(pokemon Pokemon) pokemonRecord {
@@ -239,26 +434,43 @@ func pokemonToRecord(pokemon Pokemon) pokemonRecord {
 			Slot:     ability.slot,
 		})
 	}
-	stats := make(map[string]int, len(pokemon.stats))
-	for key, value := range pokemon.stats {
-		stats[key] = value
-	}
 	moves := make([]pokemonMoveRecord, 0, len(pokemon.moves))
 	for _, move := range pokemon.moves {
+		statChanges := make([]moveStatChangeRecord, 0, len(move.statChanges))
+		for _, change := range move.statChanges {
+			statChanges = append(statChanges, moveStatChangeRecord{Stat: change.stat, Change: change.change})
+		}
 		moves = append(moves, pokemonMoveRecord{
-			Name:     move.name,
-			Power:    move.power,
-			Accuracy: move.accuracy,
-			Type:     move.moveType,
-			Priority: move.priority,
+			Name:        move.name,
+			Power:       move.power,
+			Accuracy:    move.accuracy,
+			Type:        move.moveType,
+			Priority:    move.priority,
+			PP:          move.pp,
+			MaxPP:       move.maxPP,
+			DamageClass: move.damageClass,
+			Target:      move.target,
+			StatChance:  move.statChance,
+			StatChanges: statChanges,
+			Category:    move.category,
 		})
 	}
 	return pokemonRecord{
+		UID:            pokemon.uid,
 		Name:           pokemon.name,
 		DateCaught:     pokemon.dateCaught,
 		Height:         pokemon.height,
 		Weight:         pokemon.weight,
-		Stats:          stats,
+		Stats:          copyStats(pokemon.stats),
+		BaseStats:      copyStats(pokemon.baseStats),
+		IVs:            copyStats(pokemon.ivs),
+		EVs:            copyStats(pokemon.evs),
+		EffortYield:    copyStats(pokemon.effortYield),
+		Nature:         pokemon.nature,
+		Friendship:     &pokemon.friendship,
+		Shiny:          pokemon.shiny,
+		Gender:         pokemon.gender,
+		Form:           pokemon.form,
 		Types:          append([]string(nil), pokemon.types...),
 		ID:             pokemon.id,
 		BaseExperience: pokemon.baseExperience,
@@ -276,6 +488,10 @@ func pokemonToRecord(pokemon Pokemon) pokemonRecord {
 		EvolutionChain: pokemon.evolutionChain,
 		LastXPAt:       pokemon.lastXPAt,
 		LastXPGain:     pokemon.lastXPGain,
+		CurrentHP:      pokemon.currentHP,
+		Fainted:        pokemon.fainted,
+		Status:         pokemon.status,
+		HeldItem:       pokemon.heldItem,
 	}
 }
 
@@ -288,18 +504,29 @@ func recordToPokemon(record pokemonRecord) Pokemon {
 			slot:     ability.Slot,
 		})
 	}
-	stats := make(map[string]int, len(record.Stats))
-	for key, value := range record.Stats {
-		stats[key] = value
-	}
 	moves := make([]PokemonMove, 0, len(record.Moves))
 	for _, move := range record.Moves {
+		pp, maxPP := move.PP, move.MaxPP
+		if maxPP <= 0 {
+			pp, maxPP = defaultMovePP, defaultMovePP
+		}
+		statChanges := make([]moveStatChange, 0, len(move.StatChanges))
+		for _, change := range move.StatChanges {
+			statChanges = append(statChanges, moveStatChange{stat: change.Stat, change: change.Change})
+		}
 		moves = append(moves, PokemonMove{
-			name:     move.Name,
-			power:    move.Power,
-			accuracy: move.Accuracy,
-			moveType: move.Type,
-			priority: move.Priority,
+			name:        move.Name,
+			power:       move.Power,
+			accuracy:    move.Accuracy,
+			moveType:    move.Type,
+			priority:    move.Priority,
+			pp:          pp,
+			maxPP:       maxPP,
+			damageClass: move.DamageClass,
+			target:      move.Target,
+			statChance:  move.StatChance,
+			statChanges: statChanges,
+			category:    move.Category,
 		})
 	}
 	moveCount := record.MoveCount
@@ -310,12 +537,30 @@ func recordToPokemon(record pokemonRecord) Pokemon {
 	if level <= 0 {
 		level = 1
 	}
-	return Pokemon{
+	friendship := defaultFriendship
+	if record.Friendship != nil {
+		friendship = *record.Friendship
+	}
+	uid := record.UID
+	if uid == "" {
+		uid = newPokemonUID()
+	}
+	pokemon := Pokemon{
+		uid:            uid,
 		name:           record.Name,
 		dateCaught:     record.DateCaught,
 		height:         record.Height,
 		weight:         record.Weight,
-		stats:          stats,
+		stats:          copyStats(record.Stats),
+		baseStats:      copyStats(record.BaseStats),
+		ivs:            copyStats(record.IVs),
+		evs:            copyStats(record.EVs),
+		effortYield:    copyStats(record.EffortYield),
+		nature:         record.Nature,
+		friendship:     friendship,
+		shiny:          record.Shiny,
+		gender:         record.Gender,
+		form:           record.Form,
 		types:          append([]string(nil), record.Types...),
 		id:             record.ID,
 		baseExperience: record.BaseExperience,
@@ -333,7 +578,28 @@ func recordToPokemon(record pokemonRecord) Pokemon {
 		evolutionChain: record.EvolutionChain,
 		lastXPAt:       record.LastXPAt,
 		lastXPGain:     record.LastXPGain,
+		currentHP:      record.CurrentHP,
+		fainted:        record.Fainted,
+		status:         record.Status,
+		heldItem:       record.HeldItem,
+	}
+	if pokemon.fainted {
+		pokemon.currentHP = 0
+	} else if pokemon.currentHP <= 0 || pokemon.currentHP > maxHP(pokemon) {
+		pokemon.currentHP = maxHP(pokemon)
+	}
+	return pokemon
+}
+
+func copyStats(stats map[string]int) map[string]int {
+	if stats == nil {
+		return nil
+	}
+	copied := make(map[string]int, len(stats))
+	for key, value := range stats {
+		copied[key] = value
 	}
+	return copied
 }
 
 func applyDailyGrant(c *config, now time.Time) (bool, error) {
@@ -344,8 +610,8 @@ func applyDailyGrant(c *config, now time.Time) (bool, error) {
 	if c.LastDailyGrant == key {
 		return false, nil
 	}
-	c.Inventory.Pokeball += 50
-	c.Inventory.GreatBall += 20
+	c.Bag.add("poke-ball", 50)
+	c.Bag.add("great-ball", 20)
 	c.LastDailyGrant = key
 	if err := saveUserData(c); err != nil {
 		return false, err
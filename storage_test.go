@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dey12956/pokedexcli/internal/pokeapi"
+)
+
+func TestOldSaveMovesGetTheirDetailsBack(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trainer.json")
+	old := `{"pokedex":{"pidgey":[{"uid":"p1","name":"pidgey","level":20,` +
+		`"stats":{"hp":40,"attack":45,"defense":40,"speed":56},` +
+		`"moves":[{"name":"growl","power":0,"accuracy":100,"type":"normal","priority":0},` +
+		`{"name":"tackle","power":40,"accuracy":100,"type":"normal","priority":0},` +
+		`{"name":"sand-attack","power":0,"accuracy":100,"type":"ground","priority":0}]}]}}`
+	if err := os.WriteFile(path, []byte(old), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	loaded, err := loadUserData(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	api := netTestAPI{
+		"/api/v2/move/growl": `{"name":"growl","accuracy":100,"pp":40,"priority":0,"type":{"name":"normal"},"damage_class":{"name":"status"},` +
+			`"target":{"name":"all-opponents"},"stat_changes":[{"change":-1,"stat":{"name":"attack"}}],"meta":{"stat_chance":0,"category":{"name":"net-good-stats"}}}`,
+		"/api/v2/move/tackle": `{"name":"tackle","power":40,"accuracy":100,"pp":35,"priority":0,"type":{"name":"normal"},"damage_class":{"name":"physical"},"target":{"name":"selected-pokemon"}}`,
+	}
+	c := &config{pokeapiClient: pokeapi.NewClientWithTransport(api, time.Second, time.Minute), Pokedex: make(map[string][]Pokemon)}
+	applyLoadedUserData(c, loaded)
+	if err := backfillMoveDetails(c); err == nil {
+		t.Fatalf("expected the missing sand-attack lookup to be reported")
+	}
+
+	moves := c.Pokedex["pidgey"][0].moves
+	growl, tackle, sandAttack := moves[0], moves[1], moves[2]
+	if growl.damageClass != "status" || growl.target != "all-opponents" || growl.category != "net-good-stats" ||
+		len(growl.statChanges) != 1 || growl.statChanges[0] != (moveStatChange{stat: "attack", change: -1}) {
+		t.Fatalf("expected growl's details from PokeAPI, got %+v", growl)
+	}
+	if tackle.damageClass != "physical" || tackle.target != "selected-pokemon" {
+		t.Fatalf("expected tackle's details from PokeAPI, got %+v", tackle)
+	}
+	if sandAttack.damageClass != "status" {
+		t.Fatalf("expected a move without power to fall back to a status move, got %+v", sandAttack)
+	}
+
+	user, foe := newBattlePokemon(c.Pokedex["pidgey"][0]), newBattlePokemon(c.Pokedex["pidgey"][0])
+	if damage := calculateDamage(user, foe, growl); damage != 0 {
+		t.Fatalf("expected growl to deal no damage, got %d", damage)
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+//go:embed data/trainers.json
+var trainersJSON []byte
+
+const (
+	difficultyEasy   = "easy"
+	difficultyMedium = "medium"
+	difficultyHard   = "hard"
+)
+
+type npcTrainer struct {
+	Name        string          `json:"name"`
+	DisplayName string          `json:"display_name"`
+	Prize       int             `json:"prize"`
+	Difficulty  string          `json:"difficulty"`
+	AI          string          `json:"ai,omitempty"`
+	Team        []npcTeamMember `json:"team"`
+}
+
+type npcTeamMember struct {
+	Pokemon string   `json:"pokemon"`
+	Level   int      `json:"level"`
+	Moves   []string `json:"moves"`
+}
+
+func loadTrainers() ([]npcTrainer, error) {
+	return parseTrainers(trainersJSON)
+}
+
+func parseTrainers(data []byte) ([]npcTrainer, error) {
+	var trainers []npcTrainer
+	if err := json.Unmarshal(data, &trainers); err != nil {
+		return nil, err
+	}
+	for _, trainer := range trainers {
+		if err := validateTrainer(trainer); err != nil {
+			return nil, err
+		}
+	}
+	return trainers, nil
+}
+
+func validateTrainer(trainer npcTrainer) error {
+	if trainer.Name == "" || len(trainer.Team) == 0 {
+		return fmt.Errorf("trainer %q needs a name and a team", trainer.Name)
+	}
+	switch trainer.Difficulty {
+	case difficultyEasy, difficultyMedium, difficultyHard:
+	default:
+		return fmt.Errorf("trainer %s has unknown difficulty %q", trainer.Name, trainer.Difficulty)
+	}
+	if _, exists := lookupBattleAI(trainer.AI); trainer.AI != "" && !exists {
+		return fmt.Errorf("trainer %s has unknown AI %q", trainer.Name, trainer.AI)
+	}
+	for _, member := range trainer.Team {
+		if member.Pokemon == "" || member.Level < 1 || member.Level > maxLevel {
+			return fmt.Errorf("trainer %s has an invalid team member", trainer.Name)
+		}
+		if len(member.Moves) > 4 {
+			return fmt.Errorf("trainer %s's %s knows more than 4 moves", trainer.Name, member.Pokemon)
+		}
+	}
+	return nil
+}
+
+func findTrainer(trainers []npcTrainer, name string) (npcTrainer, bool) {
+	for _, trainer := range trainers {
+		if trainer.Name == name {
+			return trainer, true
+		}
+	}
+	return npcTrainer{}, false
+}
+
+// buildTrainerTeam fetches the trainer's Pokemon at their listed levels,
+// using the listed moveset when one is given and level-up moves otherwise.
+func buildTrainerTeam(c *config, trainer npcTrainer) ([]Pokemon, error) {
+	team := make([]Pokemon, 0, len(trainer.Team))
+	for _, member := range trainer.Team {
+		resp, err := c.pokeapiClient.GetPokemon(member.Pokemon)
+		if err != nil {
+			return nil, err
+		}
+		pokemon, err := buildPokemonFromResponse(c, resp)
+		if err != nil {
+			return nil, err
+		}
+		setLevel(&pokemon, member.Level)
+		if len(member.Moves) == 0 {
+			if err := resetMoves(c, &pokemon); err != nil {
+				return nil, err
+			}
+		} else {
+			moves := make([]PokemonMove, 0, len(member.Moves))
+			for _, name := range member.Moves {
+				moveResp, err := c.pokeapiClient.GetMoveByName(name)
+				if err != nil {
+					return nil, err
+				}
+				moves = append(moves, buildMove(moveResp))
+			}
+			pokemon.moves = moves
+		}
+		pokemon.dateCaught = time.Time{}
+		restoreHP(&pokemon)
+		team = append(team, pokemon)
+	}
+	return team, nil
+}
+
+func recordTrainerDefeat(c *config, name string, at time.Time) {
+	if c.DefeatedTrainers == nil {
+		c.DefeatedTrainers = make(map[string]time.Time)
+	}
+	c.DefeatedTrainers[name] = at
+}
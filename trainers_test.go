@@ -0,0 +1,38 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTypeEffectiveness(t *testing.T) {
+	cases := []struct {
+		moveType string
+		types    []string
+		want     float64
+	}{
+		{"water", []string{"fire"}, 2},
+		{"electric", []string{"water", "flying"}, 4},
+		{"grass", []string{"fire", "flying"}, 0.25},
+		{"normal", []string{"ghost"}, 0},
+		{"typeless", []string{"normal"}, 1},
+	}
+	for _, tc := range cases {
+		if got := typeEffectiveness(tc.moveType, tc.types); math.Abs(got-tc.want) > floatTolerance {
+			t.Fatalf("%s vs %v: expected %v, got %v", tc.moveType, tc.types, tc.want, got)
+		}
+	}
+}
+
+func TestEmbeddedTrainersParse(t *testing.T) {
+	trainers, err := loadTrainers()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, exists := findTrainer(trainers, "youngster-joey"); !exists {
+		t.Fatalf("expected youngster-joey in the trainer data")
+	}
+	if _, err := parseTrainers([]byte(`[{"name":"x","difficulty":"expert","team":[{"pokemon":"pidgey","level":5}]}]`)); err == nil {
+		t.Fatalf("expected an error for an unknown difficulty")
+	}
+}
@@ -17,7 +17,58 @@ import (
 
 const asciiWidth = 40
 
-func spriteURLFromPokemon(poke pokeapi.CatchPokemonResponse) string {
+// spriteURLForPokemon picks the sprite for an individual: its own form's
+// sprite for alternate forms, otherwise the species sprite.
+func spriteURLForPokemon(c *config, pokemon Pokemon) (string, error) {
+	female := pokemon.gender == genderFemale
+	if formLabel(pokemon) != "" {
+		form, err := c.pokeapiClient.GetPokemonForm(pokemon.form)
+		if err != nil {
+			return "", err
+		}
+		sprites := form.Sprites
+		candidates := []string{sprites.FrontDefault}
+		switch {
+		case pokemon.shiny && female:
+			candidates = []string{sprites.FrontShinyFemale, sprites.FrontShiny, sprites.FrontDefault}
+		case pokemon.shiny:
+			candidates = []string{sprites.FrontShiny, sprites.FrontDefault}
+		case female:
+			candidates = []string{sprites.FrontFemale, sprites.FrontDefault}
+		}
+		for _, url := range candidates {
+			if url != "" {
+				return url, nil
+			}
+		}
+	}
+	poke, err := c.pokeapiClient.GetPokemon(pokemon.name)
+	if err != nil {
+		return "", err
+	}
+	return spriteURLFromPokemon(poke, versionSpriteKey(c), pokemon.shiny, female), nil
+}
+
+// spriteURLFromPokemon prefers the sprite from the trainer's game version,
+// then the classic sprites. Shiny Pokemon use the shiny sprites, which
+// start in generation II, and species that look different when female use
+// their female sprite.
+func spriteURLFromPokemon(poke pokeapi.CatchPokemonResponse, versionKey string, shiny, female bool) string {
+	if female {
+		femaleSprite := poke.Sprites.FrontFemale
+		if shiny {
+			femaleSprite = poke.Sprites.FrontShinyFemale
+		}
+		if url, ok := femaleSprite.(string); ok && url != "" {
+			return url
+		}
+	}
+	if url := versionSprite(poke, versionKey, shiny); url != "" {
+		return url
+	}
+	if shiny {
+		return shinySpriteURL(poke)
+	}
 	gen1 := poke.Sprites.Versions.GenerationI
 	if gen1.RedBlue.FrontDefault != "" {
 		return gen1.RedBlue.FrontDefault
@@ -47,6 +98,65 @@ func spriteURLFromPokemon(poke pokeapi.CatchPokemonResponse) string {
 	return ""
 }
 
+func shinySpriteURL(poke pokeapi.CatchPokemonResponse) string {
+	gen2 := poke.Sprites.Versions.GenerationIi
+	for _, url := range []string{
+		gen2.Crystal.FrontShiny,
+		gen2.Gold.FrontShiny,
+		gen2.Silver.FrontShiny,
+		poke.Sprites.FrontShiny,
+		poke.Sprites.Other.Showdown.FrontShiny,
+	} {
+		if url != "" {
+			return url
+		}
+	}
+	return ""
+}
+
+// versionSprite returns the front sprite from one version's sprite set, or
+// "" when that set has none.
+func versionSprite(poke pokeapi.CatchPokemonResponse, key string, shiny bool) string {
+	versions := poke.Sprites.Versions
+	var front, frontShiny string
+	switch key {
+	case "red-blue":
+		front = versions.GenerationI.RedBlue.FrontDefault
+	case "yellow":
+		front = versions.GenerationI.Yellow.FrontDefault
+	case "gold":
+		front, frontShiny = versions.GenerationIi.Gold.FrontDefault, versions.GenerationIi.Gold.FrontShiny
+	case "silver":
+		front, frontShiny = versions.GenerationIi.Silver.FrontDefault, versions.GenerationIi.Silver.FrontShiny
+	case "crystal":
+		front, frontShiny = versions.GenerationIi.Crystal.FrontDefault, versions.GenerationIi.Crystal.FrontShiny
+	case "ruby-sapphire":
+		front, frontShiny = versions.GenerationIii.RubySapphire.FrontDefault, versions.GenerationIii.RubySapphire.FrontShiny
+	case "emerald":
+		front, frontShiny = versions.GenerationIii.Emerald.FrontDefault, versions.GenerationIii.Emerald.FrontShiny
+	case "firered-leafgreen":
+		front, frontShiny = versions.GenerationIii.FireredLeafgreen.FrontDefault, versions.GenerationIii.FireredLeafgreen.FrontShiny
+	case "diamond-pearl":
+		front, frontShiny = versions.GenerationIv.DiamondPearl.FrontDefault, versions.GenerationIv.DiamondPearl.FrontShiny
+	case "platinum":
+		front, frontShiny = versions.GenerationIv.Platinum.FrontDefault, versions.GenerationIv.Platinum.FrontShiny
+	case "heartgold-soulsilver":
+		front, frontShiny = versions.GenerationIv.HeartgoldSoulsilver.FrontDefault, versions.GenerationIv.HeartgoldSoulsilver.FrontShiny
+	case "black-white":
+		front, frontShiny = versions.GenerationV.BlackWhite.FrontDefault, versions.GenerationV.BlackWhite.FrontShiny
+	case "x-y":
+		front, frontShiny = versions.GenerationVi.XY.FrontDefault, versions.GenerationVi.XY.FrontShiny
+	case "omegaruby-alphasapphire":
+		front, frontShiny = versions.GenerationVi.OmegarubyAlphasapphire.FrontDefault, versions.GenerationVi.OmegarubyAlphasapphire.FrontShiny
+	case "ultra-sun-ultra-moon":
+		front, frontShiny = versions.GenerationVii.UltraSunUltraMoon.FrontDefault, versions.GenerationVii.UltraSunUltraMoon.FrontShiny
+	}
+	if shiny {
+		return frontShiny
+	}
+	return front
+}
+
 func fetchSpriteASCII(spriteURL string) (string, error) {
 	if spriteURL == "" {
 		return "", errors.New("no sprite URL available")
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"strings"
+)
+
+const defaultVersion = "red"
+
+// gameVersion ties a PokeAPI game version to the version group its movesets
+// are listed under, the region it's set in and the key of its sprites, if
+// PokeAPI has any.
+type gameVersion struct {
+	group   string
+	region  string
+	sprites string
+}
+
+var gameVersions = map[string]gameVersion{
+	"red":               {group: "red-blue", region: "kanto", sprites: "red-blue"},
+	"blue":              {group: "red-blue", region: "kanto", sprites: "red-blue"},
+	"yellow":            {group: "yellow", region: "kanto", sprites: "yellow"},
+	"gold":              {group: "gold-silver", region: "johto", sprites: "gold"},
+	"silver":            {group: "gold-silver", region: "johto", sprites: "silver"},
+	"crystal":           {group: "crystal", region: "johto", sprites: "crystal"},
+	"ruby":              {group: "ruby-sapphire", region: "hoenn", sprites: "ruby-sapphire"},
+	"sapphire":          {group: "ruby-sapphire", region: "hoenn", sprites: "ruby-sapphire"},
+	"emerald":           {group: "emerald", region: "hoenn", sprites: "emerald"},
+	"firered":           {group: "firered-leafgreen", region: "kanto", sprites: "firered-leafgreen"},
+	"leafgreen":         {group: "firered-leafgreen", region: "kanto", sprites: "firered-leafgreen"},
+	"diamond":           {group: "diamond-pearl", region: "sinnoh", sprites: "diamond-pearl"},
+	"pearl":             {group: "diamond-pearl", region: "sinnoh", sprites: "diamond-pearl"},
+	"platinum":          {group: "platinum", region: "sinnoh", sprites: "platinum"},
+	"heartgold":         {group: "heartgold-soulsilver", region: "johto", sprites: "heartgold-soulsilver"},
+	"soulsilver":        {group: "heartgold-soulsilver", region: "johto", sprites: "heartgold-soulsilver"},
+	"black":             {group: "black-white", region: "unova", sprites: "black-white"},
+	"white":             {group: "black-white", region: "unova", sprites: "black-white"},
+	"black-2":           {group: "black-2-white-2", region: "unova", sprites: "black-white"},
+	"white-2":           {group: "black-2-white-2", region: "unova", sprites: "black-white"},
+	"x":                 {group: "x-y", region: "kalos", sprites: "x-y"},
+	"y":                 {group: "x-y", region: "kalos", sprites: "x-y"},
+	"omega-ruby":        {group: "omega-ruby-alpha-sapphire", region: "hoenn", sprites: "omegaruby-alphasapphire"},
+	"alpha-sapphire":    {group: "omega-ruby-alpha-sapphire", region: "hoenn", sprites: "omegaruby-alphasapphire"},
+	"sun":               {group: "sun-moon", region: "alola", sprites: "ultra-sun-ultra-moon"},
+	"moon":              {group: "sun-moon", region: "alola", sprites: "ultra-sun-ultra-moon"},
+	"ultra-sun":         {group: "ultra-sun-ultra-moon", region: "alola", sprites: "ultra-sun-ultra-moon"},
+	"ultra-moon":        {group: "ultra-sun-ultra-moon", region: "alola", sprites: "ultra-sun-ultra-moon"},
+	"lets-go-pikachu":   {group: "lets-go-pikachu-lets-go-eevee", region: "kanto"},
+	"lets-go-eevee":     {group: "lets-go-pikachu-lets-go-eevee", region: "kanto"},
+	"sword":             {group: "sword-shield", region: "galar"},
+	"shield":            {group: "sword-shield", region: "galar"},
+	"brilliant-diamond": {group: "brilliant-diamond-and-shining-pearl", region: "sinnoh"},
+	"shining-pearl":     {group: "brilliant-diamond-and-shining-pearl", region: "sinnoh"},
+	"legends-arceus":    {group: "legends-arceus", region: "hisui"},
+	"scarlet":           {group: "scarlet-violet", region: "paldea"},
+	"violet":            {group: "scarlet-violet", region: "paldea"},
+}
+
+// regionStarts is the town each region's trainers set out from.
+var regionStarts = map[string]string{
+	"kanto":  "pallet-town",
+	"johto":  "new-bark-town",
+	"hoenn":  "littleroot-town",
+	"sinnoh": "twinleaf-town",
+	"unova":  "nuvema-town",
+	"kalos":  "vaniville-town",
+	"alola":  "iki-town",
+	"galar":  "postwick",
+	"hisui":  "jubilife-village",
+	"paldea": "cabo-poco",
+}
+
+// versionOrder lists the versions in release order.
+var versionOrder = []string{
+	"red", "blue", "yellow",
+	"gold", "silver", "crystal",
+	"ruby", "sapphire", "emerald", "firered", "leafgreen",
+	"diamond", "pearl", "platinum", "heartgold", "soulsilver",
+	"black", "white", "black-2", "white-2",
+	"x", "y", "omega-ruby", "alpha-sapphire",
+	"sun", "moon", "ultra-sun", "ultra-moon", "lets-go-pikachu", "lets-go-eevee",
+	"sword", "shield", "brilliant-diamond", "shining-pearl", "legends-arceus",
+	"scarlet", "violet",
+}
+
+// currentVersion is the game version the trainer plays, red unless they
+// chose another.
+func currentVersion(c *config) string {
+	if c == nil || c.Version == "" {
+		return defaultVersion
+	}
+	return c.Version
+}
+
+func versionGroup(c *config) string {
+	if version, known := gameVersions[currentVersion(c)]; known {
+		return version.group
+	}
+	return gameVersions[defaultVersion].group
+}
+
+func versionRegion(c *config) string {
+	if version, known := gameVersions[currentVersion(c)]; known {
+		return version.region
+	}
+	return gameVersions[defaultVersion].region
+}
+
+func versionSpriteKey(c *config) string {
+	return gameVersions[currentVersion(c)].sprites
+}
+
+func parseVersion(input string) (string, bool) {
+	name := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(input)), " ", "-")
+	_, known := gameVersions[name]
+	return name, known
+}
+
+func versionDisplayName(version string) string {
+	return "Pokemon " + itemDisplayName(version)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestVersionCommandSwitchesAndPersists(t *testing.T) {
+	c := &config{StoragePath: filepath.Join(t.TempDir(), "trainer.json")}
+	if versionGroup(c) != "red-blue" {
+		t.Fatalf("expected red-blue movesets by default, got %q", versionGroup(c))
+	}
+	c.encounters = map[string]levelRange{"pidgey": {min: 2, max: 5}}
+	if err := commandVersion(c, "Heart Gold"); err == nil {
+		t.Fatalf("expected a version name with a space to be unknown")
+	}
+	if err := commandVersion(c, "HeartGold"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.Version != "heartgold" || versionGroup(c) != "heartgold-soulsilver" || versionSpriteKey(c) != "heartgold-soulsilver" {
+		t.Fatalf("unexpected version state %q %q %q", c.Version, versionGroup(c), versionSpriteKey(c))
+	}
+	if c.encounters != nil {
+		t.Fatalf("expected old level ranges to be dropped")
+	}
+	loaded, err := loadUserData(c.StoragePath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loaded.Version != "heartgold" {
+		t.Fatalf("expected the version to be saved, got %q", loaded.Version)
+	}
+	if err := commandVersion(c, "stadium"); err == nil {
+		t.Fatalf("expected an unknown version to be rejected")
+	}
+}
+
+func TestVersionOrderCoversEveryVersion(t *testing.T) {
+	if len(versionOrder) != len(gameVersions) {
+		t.Fatalf("expected %d versions in order, got %d", len(gameVersions), len(versionOrder))
+	}
+	for _, version := range versionOrder {
+		if _, known := gameVersions[version]; !known {
+			t.Fatalf("unknown version %s in order", version)
+		}
+	}
+}